@@ -0,0 +1,64 @@
+// i18ncheck 消息目录完整性检查(go run ./cmd/i18ncheck [-dir 目录] [-strict])
+package main
+
+import (
+	"flag"
+	"fmt"
+	_ "katydid-mp-account/internal/api/model"          // 注册本地化规则类型
+	_ "katydid-mp-account/internal/api/repo/hierarchy" // 注册业务错误消息键
+	_ "katydid-mp-account/internal/api/service"        // 注册业务错误消息键
+	"katydid-mp-account/internal/pkg/locales"
+	"katydid-mp-account/pkg/errs"
+	"katydid-mp-account/pkg/i18n"
+	"katydid-mp-account/pkg/valid"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", "", "消息目录所在文件夹(默认内置目录)")
+	strict := flag.Bool("strict", false, "有未使用的键时也失败")
+	flag.Parse()
+
+	bundle, err := loadBundle(*dir)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	keys := valid.LocalizeKeys()
+	for _, key := range errs.MsgKeys() {
+		if _, ok := keys[key]; !ok {
+			keys[key] = []string{"errs"}
+		}
+	}
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	report := bundle.Check(names)
+	for _, lang := range bundle.Langs() {
+		for _, key := range report.Missing[lang] {
+			fmt.Printf("missing\t%s\t%s\t(%s)\n", lang, key, strings.Join(keys[key], ", "))
+		}
+		for _, key := range report.Unused[lang] {
+			fmt.Printf("unused\t%s\t%s\n", lang, key)
+		}
+	}
+
+	if !report.OK() || (*strict && len(report.Unused) > 0) {
+		os.Exit(1)
+	}
+	fmt.Printf("ok: %d keys, langs %v\n", len(names), bundle.Langs())
+}
+
+func loadBundle(dir string) (*i18n.Bundle, error) {
+	if dir == "" {
+		return locales.NewBundle()
+	}
+	bundle := i18n.NewBundle(locales.DefaultLang)
+	return bundle, bundle.LoadDir(dir)
+}
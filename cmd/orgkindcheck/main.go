@@ -0,0 +1,72 @@
+// orgkindcheck 已有组织的类型包含关系检查(go run ./cmd/orgkindcheck [-in 组织导出.json] [-matrix 包含关系.json])
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"katydid-mp-account/internal/api/model"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "", "组织导出文件(JSON数组，默认读取标准输入)")
+	matrixFile := flag.String("matrix", "", "类型包含关系文件(JSON，{父级类型: [子级类型]}，默认内置规则)")
+	flag.Parse()
+
+	orgs, err := loadOrgs(*in)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	matrix, err := loadMatrix(*matrixFile)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	violations := model.CheckOrgKinds(orgs, matrix)
+	for _, v := range violations {
+		fmt.Printf("violation\t%d\t%s\tparent\t%d\t%s\n",
+			v.OrgId, model.OrgKindName(v.Kind), v.ParentId, model.OrgKindName(v.ParentKind))
+	}
+
+	if len(violations) > 0 {
+		fmt.Printf("fail: %d violations in %d orgs\n", len(violations), len(orgs))
+		os.Exit(1)
+	}
+	fmt.Printf("ok: %d orgs\n", len(orgs))
+}
+
+func loadOrgs(path string) ([]*model.Organization, error) {
+	var r io.Reader = os.Stdin
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var orgs []*model.Organization
+	if err := json.NewDecoder(r).Decode(&orgs); err != nil {
+		return nil, fmt.Errorf("decode orgs: %w", err)
+	}
+	return orgs, nil
+}
+
+func loadMatrix(path string) (model.OrgKindMatrix, error) {
+	if path == "" {
+		return model.GetOrgKindMatrix(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var matrix model.OrgKindMatrix
+	if err = json.Unmarshal(data, &matrix); err != nil {
+		return nil, fmt.Errorf("decode matrix: %w", err)
+	}
+	return matrix, matrix.Check()
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"katydid-mp-account/pkg/field"
+	"time"
+)
+
+type (
+	// OrgAudit 组织变更记录(只增不改，组织合并/删除后保留)
+	OrgAudit struct {
+		ID       field.ID   `json:"id" gorm:"primarykey;comment:主键"`
+		OrgId    field.ID   `json:"orgId" gorm:"index;comment:组织"`
+		Action   string     `json:"action" gorm:"comment:操作"`
+		TargetId field.ID   `json:"targetId" gorm:"index;comment:目标组织(移动的新父级/合并的目标组织)"`
+		Before   []field.ID `json:"before" gorm:"comment:变更前父级"`
+		After    []field.ID `json:"after" gorm:"comment:变更后父级"`
+		Detail   field.KMap `json:"detail" gorm:"type:jsonb;comment:详情"`
+		CreateAt time.Time  `json:"createAt" gorm:"autoCreateTime:milli;comment:创建时间"`
+	}
+)
+
+// 操作
+const (
+	OrgAuditMove  = "move"  // 移动(变更父级)
+	OrgAuditMerge = "merge" // 合并(子级/成员/角色转移到目标组织后删除)
+)
+
+// 详情
+const (
+	orgAuditKeyChildren = "children" // 转移的子级组织
+	orgAuditKeyMembers  = "members"  // 转移的成员数
+	orgAuditKeyRoles    = "roles"    // 转移的角色数
+	orgAuditKeySrc      = "src"      // 合并前的源组织
+	orgAuditKeyDst      = "dst"      // 合并前的目标组织
+)
+
+func NewOrgAudit(orgId field.ID, action string, targetId field.ID, before, after []field.ID) *OrgAudit {
+	return &OrgAudit{
+		OrgId: orgId, Action: action, TargetId: targetId,
+		Before: before, After: after,
+		Detail: make(field.KMap),
+	}
+}
+
+func (a *OrgAudit) SetChildren(children *[]int64) {
+	a.Detail.SetInt64Slice(orgAuditKeyChildren, children)
+}
+
+func (a *OrgAudit) GetChildren() []int64 {
+	data, _ := a.Detail.GetInt64Slice(orgAuditKeyChildren)
+	return data
+}
+
+func (a *OrgAudit) SetMembers(members *int64) {
+	a.Detail.SetInt64(orgAuditKeyMembers, members)
+}
+
+func (a *OrgAudit) GetMembers() int64 {
+	data, _ := a.Detail.GetInt64(orgAuditKeyMembers)
+	return data
+}
+
+func (a *OrgAudit) SetRoles(roles *int64) {
+	a.Detail.SetInt64(orgAuditKeyRoles, roles)
+}
+
+func (a *OrgAudit) GetRoles() int64 {
+	data, _ := a.Detail.GetInt64(orgAuditKeyRoles)
+	return data
+}
+
+func (a *OrgAudit) SetSrc(org *Organization) {
+	snapshot := orgAuditSnapshot(org)
+	a.Detail.SetMap(orgAuditKeySrc, &snapshot)
+}
+
+func (a *OrgAudit) GetSrc() field.KMap {
+	data, _ := a.Detail.GetMap(orgAuditKeySrc)
+	return data
+}
+
+func (a *OrgAudit) SetDst(org *Organization) {
+	snapshot := orgAuditSnapshot(org)
+	a.Detail.SetMap(orgAuditKeyDst, &snapshot)
+}
+
+func (a *OrgAudit) GetDst() field.KMap {
+	data, _ := a.Detail.GetMap(orgAuditKeyDst)
+	return data
+}
+
+// orgAuditSnapshot 组织快照(审计关心的结构字段)
+func orgAuditSnapshot(org *Organization) field.KMap {
+	parentIds := make([]int64, 0, len(org.ParentIds))
+	for _, parentId := range org.ParentIds {
+		parentIds = append(parentIds, parentId.Value())
+	}
+	return field.KMap{
+		"id":        org.ID.Value(),
+		"ownAccId":  org.OwnAccId.Value(),
+		"kind":      org.Kind,
+		"name":      org.Name,
+		"parentIds": parentIds,
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"katydid-mp-account/pkg/field"
+)
+
+type (
+	// OrgClosure 组织层级闭包表(祖先 -> 后代)，每个组织有一条深度为0的自身记录
+	// 多个父级(ParentIds)时同一对祖先/后代只保留最短深度，深度为1即直接父级
+	OrgClosure struct {
+		AncestorId   field.ID `json:"ancestorId" gorm:"primaryKey;comment:祖先组织"`
+		DescendantId field.ID `json:"descendantId" gorm:"primaryKey;index:idx_org_closure_desc;comment:后代组织"`
+		Depth        int      `json:"depth" gorm:"index:idx_org_closure_desc;comment:深度(0为自身)"`
+	}
+)
+
+func NewOrgClosure(ancestorId, descendantId field.ID, depth int) *OrgClosure {
+	return &OrgClosure{AncestorId: ancestorId, DescendantId: descendantId, Depth: depth}
+}
+
+// IsSelf 自身记录
+func (c *OrgClosure) IsSelf() bool {
+	return c.Depth == 0
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"fmt"
+	"katydid-mp-account/pkg/field"
+	"katydid-mp-account/pkg/valid"
+	"slices"
+	"sync"
+)
+
+type (
+	// OrgKindMatrix 类型包含关系 父级类型 -> 允许的子级类型
+	OrgKindMatrix map[uint8][]uint8
+
+	// OrgRelKinds 父级/子级组织的类型(验证类型包含关系前由仓库加载)
+	OrgRelKinds struct {
+		Parents  map[field.ID]uint8 // 父级组织 -> 类型
+		Children map[field.ID]uint8 // 子级组织 -> 类型
+	}
+
+	// OrgKindViolation 类型包含关系冲突(迁移检查)
+	OrgKindViolation struct {
+		OrgId      field.ID `json:"orgId"`
+		Kind       uint8    `json:"kind"`
+		ParentId   field.ID `json:"parentId"`
+		ParentKind uint8    `json:"parentKind"`
+	}
+)
+
+// 类型包含关系标签
+const (
+	orgTagKindParent   valid.Tag = "kind-parent"   // 父级类型不能包含当前类型 {父级类型}
+	orgTagKindChild    valid.Tag = "kind-child"    // 当前类型不能包含子级类型 {子级类型}
+	orgTagKindUnloaded valid.Tag = "kind-unloaded" // 未加载父级/子级类型(未调用 SetRelKinds)
+)
+
+// orgKindNames 类型名称(消息键)
+var orgKindNames = map[uint8]string{
+	OrgKindGroup:   "org_kind_group",
+	OrgKindCompany: "org_kind_company",
+	OrgKindStudio:  "org_kind_studio",
+	OrgKindTeam:    "org_kind_team",
+}
+
+var (
+	orgKindMu     sync.RWMutex
+	orgKindMatrix = DefaultOrgKindMatrix()
+)
+
+// DefaultOrgKindMatrix 默认类型包含关系(集团 > 公司 > 工作室 > 团队，团队可以挂在任意组织下)
+func DefaultOrgKindMatrix() OrgKindMatrix {
+	return OrgKindMatrix{
+		OrgKindGroup:   {OrgKindGroup, OrgKindCompany, OrgKindStudio, OrgKindTeam},
+		OrgKindCompany: {OrgKindCompany, OrgKindStudio, OrgKindTeam},
+		OrgKindStudio:  {OrgKindTeam},
+		OrgKindTeam:    {OrgKindTeam},
+	}
+}
+
+// SetOrgKindMatrix 配置类型包含关系(启动时调用)，包含未知类型时返回错误
+func SetOrgKindMatrix(matrix OrgKindMatrix) error {
+	if err := matrix.Check(); err != nil {
+		return err
+	}
+	orgKindMu.Lock()
+	defer orgKindMu.Unlock()
+	orgKindMatrix = matrix.Clone()
+	return nil
+}
+
+// GetOrgKindMatrix 当前类型包含关系(拷贝)
+func GetOrgKindMatrix() OrgKindMatrix {
+	orgKindMu.RLock()
+	defer orgKindMu.RUnlock()
+	return orgKindMatrix.Clone()
+}
+
+// OrgKindName 类型名称(消息键)，未知类型返回数字
+func OrgKindName(kind uint8) string {
+	if name, ok := orgKindNames[kind]; ok {
+		return name
+	}
+	return fmt.Sprint(kind)
+}
+
+// Check 检查类型是否都已定义
+func (m OrgKindMatrix) Check() error {
+	for parent, children := range m {
+		if _, ok := orgKindNames[parent]; !ok {
+			return fmt.Errorf("org kind matrix: unknown parent kind %d", parent)
+		}
+		for _, child := range children {
+			if _, ok := orgKindNames[child]; !ok {
+				return fmt.Errorf("org kind matrix: unknown child kind %d (parent %d)", child, parent)
+			}
+		}
+	}
+	return nil
+}
+
+// Clone 深拷贝
+func (m OrgKindMatrix) Clone() OrgKindMatrix {
+	clone := make(OrgKindMatrix, len(m))
+	for parent, children := range m {
+		clone[parent] = slices.Clone(children)
+	}
+	return clone
+}
+
+// CanContain 父级类型是否允许包含子级类型
+func (m OrgKindMatrix) CanContain(parent, child uint8) bool {
+	return slices.Contains(m[parent], child)
+}
+
+// CanContainOrgKind 当前配置下父级类型是否允许包含子级类型
+func CanContainOrgKind(parent, child uint8) bool {
+	orgKindMu.RLock()
+	defer orgKindMu.RUnlock()
+	return orgKindMatrix.CanContain(parent, child)
+}
+
+// SetRelKinds 设置父级/子级组织的类型(用于新增/移动时的类型包含关系验证)
+func (o *Organization) SetRelKinds(kinds *OrgRelKinds) {
+	o.relKinds = kinds
+}
+
+// validKinds 类型包含关系(父级能否包含自身，自身能否包含子级)，同一类型只上报一次
+// 新增(有父级)/移动/合并时未加载父级/子级类型视为验证失败，避免遗漏 SetRelKinds 时跳过验证
+func (o *Organization) validKinds(scene valid.Scene, fn valid.FuncReportError) {
+	if o.relKinds == nil {
+		switch {
+		case scene == OrgSceneMerge:
+			fn(o.Kind, "Kind", orgTagKindUnloaded, "")
+		case scene == OrgSceneMove || len(o.ParentIds) > 0:
+			fn(o.ParentIds, "ParentIds", orgTagKindUnloaded, "")
+		}
+		return
+	}
+	for _, kind := range sortedKinds(o.relKinds.Parents) {
+		if !CanContainOrgKind(kind, o.Kind) {
+			fn(o.ParentIds, "ParentIds", orgTagKindParent, OrgKindName(kind))
+		}
+	}
+	for _, kind := range sortedKinds(o.relKinds.Children) {
+		if !CanContainOrgKind(o.Kind, kind) {
+			fn(o.Kind, "Kind", orgTagKindChild, OrgKindName(kind))
+		}
+	}
+}
+
+// LocalizeParamKeys 类型包含关系错误的参数为类型名称
+func (o *Organization) LocalizeParamKeys() []string {
+	keys := make([]string, 0, len(orgKindNames))
+	for _, name := range orgKindNames {
+		keys = append(keys, name)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// CheckOrgKinds 检查已有组织的类型包含关系(迁移前报告冲突，父级不在 orgs 中时跳过)
+func CheckOrgKinds(orgs []*Organization, matrix OrgKindMatrix) []OrgKindViolation {
+	kinds := make(map[field.ID]uint8, len(orgs))
+	for _, org := range orgs {
+		kinds[org.ID] = org.Kind
+	}
+	var violations []OrgKindViolation
+	for _, org := range orgs {
+		for _, parentId := range org.ParentIds {
+			parentKind, ok := kinds[parentId]
+			if ok && !matrix.CanContain(parentKind, org.Kind) {
+				violations = append(violations, OrgKindViolation{
+					OrgId: org.ID, Kind: org.Kind, ParentId: parentId, ParentKind: parentKind,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// sortedKinds 去重排序后的类型
+func sortedKinds(ids map[field.ID]uint8) []uint8 {
+	kinds := make([]uint8, 0, len(ids))
+	for _, kind := range ids {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	return slices.Compact(kinds)
+}
@@ -1,28 +1,50 @@
 package model
 
 import (
+	"context"
 	"katydid-mp-account/internal/pkg/model"
 	"katydid-mp-account/pkg/field"
 	"katydid-mp-account/pkg/valid"
-	"reflect"
-	"unicode"
-	"unicode/utf8"
+	"slices"
 )
 
 type (
 	Organization struct {
 		model.Base
 
-		OwnAccId  field.ID   `json:"ownAccId" validate:"required" gorm:"comment:所属账号"`
+		OwnAccId  field.ID   `json:"ownAccId" validate:"required" gorm:"uniqueIndex:uk_org_owner_name;comment:所属账号"`
 		ParentIds []field.ID `json:"parentIds" gorm:"comment:父级组织"`
 
 		IsPrivate bool     `json:"isPrivate" gorm:"comment:是否私有"`
-		Kind      uint8    `json:"kind" validate:"kind-check" gorm:"comment:组织类型"`
-		Become    uint8    `json:"become" validate:"become-check" gorm:"comment:加入方式"`
-		Name      string   `json:"name" validate:"name-format" gorm:"comment:组织名称"`
-		Display   string   `json:"display" validate:"display-format" gorm:"comment:组织显示名称"`
-		Tags      []string `json:"tags" validate:"tags-format,tag-format" gorm:"comment:组织标签们"`
+		Kind      uint8    `json:"kind" gorm:"comment:组织类型"`
+		Become    uint8    `json:"become" gorm:"comment:加入方式"`
+		Name      string   `json:"name" gorm:"uniqueIndex:uk_org_owner_name;comment:组织名称"`
+		Display   string   `json:"display" gorm:"comment:组织显示名称"`
+		Tags      []string `json:"tags" gorm:"comment:组织标签们"`
+
+		relKinds *OrgRelKinds // 父级/子级组织的类型(不入库，见 SetRelKinds)
+	}
+)
+
+// 验证依赖(由仓库实现，通过 valid.Provide 注入)
+type (
+	IAccountChecker interface {
+		IsAccountActive(ctx context.Context, accId field.ID) (bool, error)
 	}
+	IOrgChecker interface {
+		IsOrgsExist(ctx context.Context, orgIds []field.ID) (bool, error)
+	}
+)
+
+// 提示规则(警告，不阻止保存)
+const (
+	orgTagFaviconMiss   valid.Tag = "favicon-missing" // 未设置图标
+	orgTagDescNearLimit valid.Tag = "desc-near-limit" // 简介接近长度上限
+)
+
+// 数据库约束
+const (
+	orgConstraintOwnerName = "uk_org_owner_name" // 同所属账号下名称唯一
 )
 
 // 类型
@@ -40,6 +62,19 @@ const (
 	OrgBecomeInvite uint8 = 2 // 邀请制
 )
 
+func init() {
+	// 覆盖 Base 的规则方法(ValidFieldRules/ValidFieldDeps 使用 Base 的)
+	valid.RegisterOwnMethods(&Organization{}, "ViewRules", "ValidExtraRules", "ValidStructRules", "ValidLocalizeRules")
+	// 注册本地化规则(启动时检查规则格式，并用于消息目录完整性检查)
+	if err := valid.RegisterLocalizer(&Organization{}); err != nil {
+		panic(err)
+	}
+	// 名称唯一约束冲突 -> 名称唯一性验证错误
+	valid.RegisterConstraint(orgConstraintOwnerName, "Name", valid.TagUnique, valid.UniqueOwner.String())
+	// 敏感键(按键加密，历史明文读出后下次保存时加密)
+	field.RegisterSecretKeys(orgExtKeyRootPwd)
+}
+
 func NewOrganizationEmpty() *Organization {
 	return &Organization{
 		Base:      model.NewBase(0),
@@ -59,67 +94,105 @@ func NewOrganization(
 	}
 }
 
-// 验证场景
-const (
-	OrgSceneUpdateName   valid.Scene = valid.SceneCustom + 1 // 更新名称
-	OrgSceneUpdateBecome valid.Scene = valid.SceneCustom + 2 // 更新加入方式
+// Clone 深拷贝，缓存中的组织需拷贝后再交给其他协程读写
+func (o *Organization) Clone() *Organization {
+	clone := *o
+	clone.Base = o.Base.Clone()
+	clone.ParentIds = slices.Clone(o.ParentIds)
+	clone.Tags = slices.Clone(o.Tags)
+	return &clone
+}
+
+func (o *Organization) ViewRules() valid.ViewRules {
+	return valid.ViewRules{
+		valid.SceneAll: valid.ViewRule{
+			Fields: map[valid.FieldName]valid.Audience{
+				"OwnAccId": valid.AudienceMember,
+			},
+			Extra: map[string]valid.Audience{
+				orgExtKeyRootPwd:  valid.AudienceInternal,
+				orgExtKeyMultiJob: valid.AudienceMember,
+
+				orgExtKeyWebsiteUrl: valid.AudiencePublic,
+				orgExtKeyFaviconUrl: valid.AudiencePublic,
+				orgExtKeyDesc:       valid.AudiencePublic,
+				orgExtKeyAddresses:  valid.AudiencePublic,
+				orgExtKeyContacts:   valid.AudiencePublic,
+				orgExtKeyCertImgs:   valid.AudienceMember,
+			},
+		},
+	}
+}
+
+// 验证场景(继承更新场景的规则)
+var (
+	OrgSceneUpdateName   = valid.MustRegisterScene("org.updateName", valid.SceneUpd)   // 更新名称
+	OrgSceneUpdateBecome = valid.MustRegisterScene("org.updateBecome", valid.SceneUpd) // 更新加入方式
+	OrgSceneMove         = valid.MustRegisterScene("org.move", valid.SceneUpd)         // 移动(变更父级)
+	OrgSceneMerge        = valid.MustRegisterScene("org.merge", valid.SceneUpd)        // 合并(目标组织接收子级)
 )
 
-func (o *Organization) ValidFieldRules() valid.FieldValidRules {
-	return valid.FieldValidRules{
-		valid.SceneInsert: valid.FieldValidRule{
+func (o *Organization) ValidRules() valid.RuleValidRules {
+	return valid.RuleValidRules{
+		valid.SceneSave: valid.RuleValidRule{
 			// 组织类型
-			"kind-check": func(value reflect.Value, param string) bool {
-				v := uint8(value.Uint())
-				return v == OrgKindGroup || v == OrgKindCompany || v == OrgKindStudio || v == OrgKindTeam
-			},
+			"Kind": valid.Enum(OrgKindGroup, OrgKindCompany, OrgKindStudio, OrgKindTeam).Label("org_kind"),
 			// 加入方式
-			"become-check": func(value reflect.Value, param string) bool {
-				v := uint8(value.Uint())
-				return v == OrgBecomeDirect || v == OrgBecomeApply || v == OrgBecomeInvite
-			},
+			"Become": valid.Enum(OrgBecomeDirect, OrgBecomeApply, OrgBecomeInvite).Label("org_become"),
 			// 名称(全) (1-50)
-			"name-format": func(value reflect.Value, param string) bool {
-				v := value.String()
-				if utf8.RuneCountInString(v) < 1 || utf8.RuneCountInString(v) > 50 {
-					return false
-				}
-				for _, r := range v {
-					if !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_' && r != '-' {
-						return false
-					}
-				}
-				return true
-			},
+			"Name": valid.Runes(1, 50).Charset(valid.Word).Label("org_name"),
 			// 名称(简) (0-25)
-			"display-format": func(value reflect.Value, param string) bool {
-				v := value.String()
-				if utf8.RuneCountInString(v) > 25 {
-					return false
-				}
-				for _, r := range v {
-					if !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_' && r != '-' {
-						return false
+			"Display": valid.Runes(0, 25).Charset(valid.Word).Label("org_display"),
+			// 组织标签们 (0-10)*(1-20)
+			"Tags": valid.Slice(0, 10).Each(valid.Runes(1, 20)).Label("org_tags"),
+		},
+	}
+}
+
+func (o *Organization) ValidContextRules() valid.ContextValidRules {
+	return valid.ContextValidRules{
+		valid.SceneAdd: valid.ContextValidRule{
+			// 所属账号(存在且可用)
+			"own-check": valid.ContextValidRuleInfo{
+				Field: "OwnAccId",
+				ValidFn: func(ctx context.Context) (bool, error) {
+					checker, err := valid.Dep[IAccountChecker](ctx)
+					if err != nil {
+						return false, err
 					}
-				}
-				return true
+					return checker.IsAccountActive(ctx, o.OwnAccId)
+				},
 			},
-
-			// TODO:GG 这里了
-			// 组织标签们 (0-10)*(1-20)
-			"tags-format": func(value reflect.Value, param string) bool {
-				data := value.Interface().([]string)
-				if len(data) > 10 {
-					return false
-				}
-				for _, v := range data {
-					if len(v) < 1 || len(v) > 20 {
-						return false
+			// 父级组织(全部存在)
+			"parent-check": valid.ContextValidRuleInfo{
+				Field: "ParentIds",
+				ValidFn: func(ctx context.Context) (bool, error) {
+					if len(o.ParentIds) == 0 {
+						return true, nil
 					}
-				}
-				return true
+					checker, err := valid.Dep[IOrgChecker](ctx)
+					if err != nil {
+						return false, err
+					}
+					return checker.IsOrgsExist(ctx, o.ParentIds)
+				},
 			},
+			// 名称唯一
+			valid.TagUnique: valid.Unique(o.nameUnique(nil)),
 		},
+		valid.SceneUpd: valid.ContextValidRule{
+			// 名称唯一(排除自身)
+			valid.TagUnique: valid.Unique(o.nameUnique(o.ID)),
+		},
+	}
+}
+
+// nameUnique 名称唯一性(同所属账号)
+func (o *Organization) nameUnique(excludeID any) valid.UniqueQuery {
+	return valid.UniqueQuery{
+		Entity: "organization", Field: "Name", Value: o.Name,
+		Scope: valid.UniqueOwner, ScopeField: "OwnAccId", ScopeValue: o.OwnAccId,
+		ExcludeID: excludeID,
 	}
 }
 
@@ -129,87 +202,88 @@ func (o *Organization) ValidExtraRules() (field.KMap, valid.ExtraValidRules) {
 			// 官网 (<1000)
 			orgExtKeyWebsiteUrl: valid.ExtraValidRuleInfo{
 				Field: orgExtKeyWebsiteUrl,
-				ValidFn: func(value any) bool {
-					data, ok := value.(string)
-					if !ok {
-						return false
-					}
-					return len(data) <= 1000
-				},
+				Rule:  valid.Runes(0, 1000),
+			},
+			// 图标 (<1000)
+			orgExtKeyFaviconUrl: valid.ExtraValidRuleInfo{
+				Field: orgExtKeyFaviconUrl,
+				Rule:  valid.Runes(0, 1000),
 			},
 			// 简介 (<1000)
 			orgExtKeyDesc: valid.ExtraValidRuleInfo{
 				Field: orgExtKeyDesc,
-				ValidFn: func(value any) bool {
-					data, ok := value.(string)
-					if !ok {
-						return false
-					}
-					return len(data) <= 1000
-				},
+				Rule:  valid.Runes(0, 1000),
+			},
+			// 简介接近上限 (>900 警告)
+			orgTagDescNearLimit: valid.ExtraValidRuleInfo{
+				Field: orgExtKeyDesc,
+				Rule:  valid.Runes(0, 900).Warn(),
 			},
 			// 地址 (<100)*(<1000)
 			orgExtKeyAddresses: valid.ExtraValidRuleInfo{
 				Field: orgExtKeyAddresses,
-				ValidFn: func(value any) bool {
-					data, ok := value.([]string)
-					if !ok {
-						return false
-					}
-					if len(data) > 100 {
-						return false
-					}
-					for _, v := range data {
-						if len(v) > 1000 {
-							return false
-						}
-					}
-					return true
-				},
+				Rule:  valid.Slice(0, 100).Each(valid.Runes(0, 1000)),
 			},
 			// 联系方式 (<100)*(<1000)
 			orgExtKeyContacts: valid.ExtraValidRuleInfo{
 				Field: orgExtKeyContacts,
-				ValidFn: func(value any) bool {
-					data, ok := value.([]string)
-					if !ok {
-						return false
-					}
-					if len(data) > 100 {
-						return false
-					}
-					for _, v := range data {
-						if len(v) > 1000 {
-							return false
-						}
-					}
-					return true
-				},
+				Rule:  valid.Slice(0, 100).Each(valid.Runes(0, 1000)),
 			},
 		},
 	}
 }
 
+// ValidExtraKeys Extra键声明(请求绑定时拒绝未声明的键，保存时保留历史数据中的键)
+func (o *Organization) ValidExtraKeys() valid.ExtraKeyRules {
+	return valid.ExtraKeyRules{
+		valid.SceneAll: valid.ExtraKeyRule{
+			Keys: []string{orgExtKeyMultiJob, orgExtKeyCertImgs},
+		},
+		valid.SceneBind: valid.ExtraKeyRule{
+			Policy:   valid.ExtraReject,
+			ReadOnly: []string{orgExtKeyRootPwd}, // 只能通过设置根密码的流程修改
+		},
+	}
+}
+
+// ValidStructRules 结构体验证规则
+func (o *Organization) ValidStructRules(scene valid.Scene, fn valid.FuncReportError) {
+	switch scene {
+	case valid.SceneAdd, valid.SceneUpd:
+		if o.GetFaviconUrl() == "" {
+			fn(nil, valid.ExtraField+"."+orgExtKeyFaviconUrl, orgTagFaviconMiss, "")
+		}
+	}
+	switch scene {
+	case valid.SceneAdd, OrgSceneMove, OrgSceneMerge:
+		o.validKinds(scene, fn)
+	}
+}
+
 func (o *Organization) ValidLocalizeRules() valid.LocalizeValidRules {
 	return valid.LocalizeValidRules{
 		valid.SceneAll: valid.LocalizeValidRule{
 			Rule1: map[valid.Tag]map[valid.FieldName]valid.LocalizeValidRuleParam{
 				valid.TagRequired: {
-					"OwnAccIds": {"format_s_input_required", false, []any{"own_accounts"}},
-					"Name":      {"format_s_input_required", false, []any{"org_name"}},
+					"OwnAccId": {Msg: "format_s_input_required", Template: []any{"own_account"}},
+					"Name":     {Msg: "format_s_input_required", Template: []any{"org_name"}},
+				},
+				valid.TagUnique: {
+					"Name": {Msg: "rule_unique_err", Template: []any{"org_name"}},
 				},
 			}, Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{
-				"own-check":         {"format_org_own_accs_err", false, nil},
-				"parent-check":      {"format_org_parents_err", false, nil},
-				"name-format":       {"format_org_name_err", false, nil},
-				"display-format":    {"format_org_display_err", false, nil},
-				"kind-check":        {"format_org_kind_err", false, nil},
-				"become-check":      {"format_org_become_err", false, nil},
-				"tags-format":       {"format_org_tags_err", false, nil},
-				orgExtKeyWebsiteUrl: {"format_website_err", false, nil},
-				orgExtKeyDesc:       {"format_desc_err", false, nil},
-				orgExtKeyAddresses:  {"format_addresses_err", false, nil},
-				orgExtKeyContacts:   {"format_contacts_err", false, nil},
+				"own-check":         {Msg: "format_org_own_accs_err"},
+				"parent-check":      {Msg: "format_org_parents_err"},
+				orgTagKindParent:    {Msg: "org_kind_parent_err", WithParam: true, KeyParam: true},
+				orgTagKindChild:     {Msg: "org_kind_child_err", WithParam: true, KeyParam: true},
+				orgTagKindUnloaded:  {Msg: "org_kind_unloaded_err"},
+				orgExtKeyWebsiteUrl: {Msg: "format_website_err"},
+				orgExtKeyFaviconUrl: {Msg: "format_favicon_err"},
+				orgExtKeyDesc:       {Msg: "format_desc_err"},
+				orgTagDescNearLimit: {Msg: "warn_org_desc_near_limit"},
+				orgTagFaviconMiss:   {Msg: "warn_org_favicon_missing", Severity: valid.SeverityWarning},
+				orgExtKeyAddresses:  {Msg: "format_addresses_err"},
+				orgExtKeyContacts:   {Msg: "format_contacts_err"},
 			},
 		},
 	}
@@ -218,7 +292,7 @@ func (o *Organization) ValidLocalizeRules() valid.LocalizeValidRules {
 // extra
 const (
 	// TODO:GG 有成员的时候，获取需要各种auth?登录不需要
-	orgExtKeyRootPwd  = "rootPwd"  // 根密码
+	orgExtKeyRootPwd  = "rootPwd"  // 根密码 (敏感值，入库加密，输出脱敏)
 	orgExtKeyMultiJob = "multiJob" // 是否允许单用户多任职
 
 	orgExtKeyWebsiteUrl = "websiteUrl" // 官网
@@ -226,18 +300,18 @@ const (
 	orgExtKeyDesc       = "desc"       // 简介
 	orgExtKeyAddresses  = "addresses"  // 地址
 	orgExtKeyContacts   = "contacts"   // 联系方式
-	orgExtKeyCertImgs   = "contacts"   // 联系方式
+	orgExtKeyCertImgs   = "certImgs"   // 认证图片
 
 	// TODO:GG 支持的Account的认证方式? 支持的Permission的方式?
 	// TODO:GG PasswordType, PasswordSalt
 )
 
 func (o *Organization) SetRootPwd(pwd *string) {
-	o.Extra.SetString(orgExtKeyRootPwd, pwd)
+	o.Extra.SetSecret(orgExtKeyRootPwd, pwd)
 }
 
 func (o *Organization) GetRootPwd() string {
-	data, _ := o.Extra.GetString(orgExtKeyRootPwd)
+	data, _ := o.Extra.GetSecret(orgExtKeyRootPwd)
 	return data
 }
 
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"katydid-mp-account/pkg/field"
+	"katydid-mp-account/pkg/valid"
+	"testing"
+)
+
+// newBenchOrg 常见字段都有值的组织
+func newBenchOrg() *Organization {
+	org := NewOrganization(1, []field.ID{2, 3}, false, OrgKindCompany, OrgBecomeApply,
+		"katydid", "katydid", []string{"go", "account"})
+	website, favicon, desc := "https://katydid.example.com", "https://katydid.example.com/favicon.ico", "katydid"
+	org.SetWebsiteUrl(&website)
+	org.SetFaviconUrl(&favicon)
+	org.SetDesc(&desc)
+	org.SetAddresses(&[]string{"address line 1"})
+	org.SetRelKinds(&OrgRelKinds{Parents: map[field.ID]uint8{2: OrgKindGroup, 3: OrgKindGroup}})
+	return org
+}
+
+// BenchmarkCheck Organization 验证(请求绑定/保存/失败)
+func BenchmarkCheck(b *testing.B) {
+	org, invalid := newBenchOrg(), newBenchOrg()
+	invalid.Name, invalid.Tags = "katydid!", []string{""}
+	if errs, _ := valid.Check(org, valid.SceneAdd); len(errs) != 0 {
+		b.Fatalf("valid org: err %s %s", errs[0].Field, errs[0].Tag)
+	}
+	if errs, _ := valid.Check(invalid, valid.SceneAdd); len(errs) == 0 {
+		b.Fatal("invalid org passed")
+	}
+
+	cases := []struct {
+		name  string
+		org   *Organization
+		scene valid.Scene
+	}{
+		{"bind", org, valid.SceneBind},
+		{"add", org, valid.SceneAdd},
+		{"add-invalid", invalid, valid.SceneAdd},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = valid.Check(c.org, c.scene)
+			}
+		})
+	}
+}
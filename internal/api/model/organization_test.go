@@ -0,0 +1,184 @@
+package model
+
+import (
+	"encoding/json"
+	"katydid-mp-account/pkg/field"
+	"katydid-mp-account/pkg/valid"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func withTestCipher(t *testing.T) {
+	t.Helper()
+	c, err := field.NewEnvelopeCipher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	field.SetSecretCipher(c)
+	t.Cleanup(func() { field.SetSecretCipher(nil) })
+}
+
+func TestOrganizationRootPwdJSON(t *testing.T) {
+	withTestCipher(t)
+
+	pwd := "root-pwd"
+	org := NewOrganization(1, nil, false, OrgKindGroup, OrgBecomeDirect, "org", "Org", nil)
+	org.SetRootPwd(&pwd)
+
+	// 缓存/队列(JSON)无损
+	data, err := json.Marshal(org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), pwd) {
+		t.Fatalf("json contains plaintext: %s", data)
+	}
+	cached := NewOrganizationEmpty()
+	if err = json.Unmarshal(data, cached); err != nil {
+		t.Fatal(err)
+	}
+	if got := cached.GetRootPwd(); got != pwd {
+		t.Fatalf("GetRootPwd after json round-trip = %q, want %q", got, pwd)
+	}
+
+	// 响应视图脱敏
+	view, err := valid.ViewJSON(cached, valid.AudienceInternal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(view), pwd) || strings.Contains(string(view), `"$secret"`) {
+		t.Fatalf("view leaks secret: %s", view)
+	}
+}
+
+func TestOrganizationNameRuleScenes(t *testing.T) {
+	tests := []struct {
+		scene valid.Scene
+		name  string
+		fail  bool
+	}{
+		{valid.SceneAdd, "bad name!!", true},
+		{valid.SceneUpd, "bad name!!", true},
+		{OrgSceneUpdateName, "bad name!!", true},
+		{OrgSceneUpdateName, "good_name", false},
+		{valid.SceneGet, "bad name!!", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scene.String(), func(t *testing.T) {
+			org := NewOrganization(1, nil, false, OrgKindGroup, OrgBecomeDirect, tt.name, "", nil)
+			errs, _ := valid.Check(org, tt.scene)
+			failed := false
+			for _, e := range errs {
+				if e.Field == "name" {
+					failed = true
+				}
+			}
+			if failed != tt.fail {
+				t.Fatalf("name %q failed = %v, want %v (errs %v)", tt.name, failed, tt.fail, errs)
+			}
+		})
+	}
+}
+
+func TestOrganizationCheckKeepsExtra(t *testing.T) {
+	for _, scene := range []valid.Scene{valid.SceneAdd, valid.SceneUpd, OrgSceneMove} {
+		org := NewOrganization(1, nil, false, OrgKindGroup, OrgBecomeDirect, "org", "", nil)
+		org.Extra["junk"] = "legacy"
+		valid.Check(org, scene)
+		if org.Extra["junk"] != "legacy" {
+			t.Fatalf("%s: Check removed undeclared Extra key", scene)
+		}
+	}
+}
+
+func TestOrganizationKindsUnloaded(t *testing.T) {
+	tests := []struct {
+		scene     valid.Scene
+		parentIds []field.ID
+		kinds     *OrgRelKinds
+		field     string // 空: 不报告未加载
+	}{
+		{valid.SceneAdd, nil, nil, ""},
+		{valid.SceneAdd, []field.ID{2}, nil, "parentIds"},
+		{valid.SceneAdd, []field.ID{2}, &OrgRelKinds{Parents: map[field.ID]uint8{2: OrgKindGroup}}, ""},
+		{OrgSceneMove, nil, nil, "parentIds"},
+		{OrgSceneMerge, nil, nil, "kind"},
+		{OrgSceneMerge, nil, &OrgRelKinds{}, ""},
+		{valid.SceneUpd, []field.ID{2}, nil, ""},
+	}
+	for _, tt := range tests {
+		org := NewOrganization(1, tt.parentIds, false, OrgKindGroup, OrgBecomeDirect, "org", "", nil)
+		org.SetRelKinds(tt.kinds)
+		errs, _ := valid.Check(org, tt.scene)
+		got := ""
+		for _, e := range errs {
+			if e.Tag == string(orgTagKindUnloaded) {
+				got = e.Field
+			}
+		}
+		if got != tt.field {
+			t.Errorf("%s parents %v kinds %v: unloaded field = %q, want %q", tt.scene, tt.parentIds, tt.kinds, got, tt.field)
+		}
+	}
+}
+
+func TestOrganizationLocalizeParamKeysSorted(t *testing.T) {
+	keys := NewOrganizationEmpty().LocalizeParamKeys()
+	if !slices.IsSorted(keys) || len(keys) != len(orgKindNames) {
+		t.Fatalf("LocalizeParamKeys = %v", keys)
+	}
+}
+
+func TestOrganizationRootPwdLimits(t *testing.T) {
+	field.SetSecretCipher(nil)
+	pwd := "root-pwd"
+	org := NewOrganization(1, nil, false, OrgKindGroup, OrgBecomeDirect, "org", "", nil)
+	org.SetRootPwd(&pwd)
+
+	errs, _ := valid.Check(org, valid.SceneAdd)
+	for _, e := range errs {
+		if e.Field == "extra" {
+			t.Fatalf("Check reported %s on extra without cipher", e.Tag)
+		}
+	}
+}
+
+func TestOrganizationUrlRules(t *testing.T) {
+	tests := []struct {
+		url  string
+		fail bool
+	}{
+		{"https://katydid.example.com/favicon.ico", false},
+		{"/static/favicon.ico", false}, // 历史数据中的相对路径
+		{"", false},
+		{strings.Repeat("u", 1001), true},
+	}
+	for _, tt := range tests {
+		org := NewOrganization(1, nil, false, OrgKindGroup, OrgBecomeDirect, "org", "", nil)
+		org.SetWebsiteUrl(&tt.url)
+		org.SetFaviconUrl(&tt.url)
+		errs, _ := valid.Check(org, valid.SceneUpd)
+		var fields []string
+		for _, e := range errs {
+			if e.Field == "extra.websiteUrl" || e.Field == "extra.faviconUrl" {
+				fields = append(fields, e.Field)
+			}
+		}
+		if want := map[bool]int{true: 2}[tt.fail]; len(fields) != want {
+			t.Errorf("url %.20q: failed fields %v, want %d", tt.url, fields, want)
+		}
+	}
+}
+
+func TestOrganizationSchemaDesc(t *testing.T) {
+	for i := 0; i < 50; i++ { // 规则为 map，多次导出确认结果稳定
+		for _, scene := range []valid.Scene{valid.SceneAdd, valid.SceneUpd} {
+			extra := valid.Schema(&Organization{}, scene).Properties[valid.ExtraJSONField]
+			desc := extra.Properties[orgExtKeyDesc]
+			if desc == nil || desc.MaxLength == nil || *desc.MaxLength != 1000 {
+				t.Fatalf("scene %v extra.desc = %+v, want maxLength 1000", scene, desc)
+			}
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package hierarchy 组织层级(闭包表)，维护祖先/后代记录并提供层级查询
+//
+// 目前只有内存实现 MemStore，闭包表记录不会持久化(重启后需按 Organization.ParentIds 重新 Insert)
+// TODO: 基于 model.OrgClosure 的数据库 IStore 实现及迁移
+package hierarchy
+
+import (
+	"cmp"
+	"context"
+	"katydid-mp-account/internal/api/model"
+	"katydid-mp-account/pkg/errs"
+	"katydid-mp-account/pkg/field"
+	"slices"
+)
+
+// 错误消息键
+const (
+	MsgCycle          = "err_org_hierarchy_cycle"            // 父级不能是自身或后代 {组织, 父级}
+	MsgParentNotFound = "err_org_hierarchy_parent_not_found" // 父级不存在 {组织, 父级}
+)
+
+// ErrCycle 形成循环(errors.Is 判断)
+var ErrCycle = errs.FailedPrecondition(MsgCycle)
+
+func init() {
+	errs.RegisterMsg(MsgCycle, MsgParentNotFound)
+}
+
+type (
+	// IStore 闭包表存储(数据库实现需在事务中调用 Repo 的写操作)
+	IStore interface {
+		// Exists 组织是否有层级记录(自身记录)
+		Exists(ctx context.Context, id field.ID) (bool, error)
+		// Ancestors 祖先记录(不含自身)，按深度排序
+		Ancestors(ctx context.Context, id field.ID) ([]*model.OrgClosure, error)
+		// Descendants 后代记录(不含自身)，按深度排序，depth<=0 时不限深度
+		Descendants(ctx context.Context, id field.ID, depth int) ([]*model.OrgClosure, error)
+		// Replace 替换组织作为后代的全部记录(含自身记录)
+		Replace(ctx context.Context, id field.ID, rows []*model.OrgClosure) error
+		// Remove 删除组织作为祖先/后代的全部记录
+		Remove(ctx context.Context, id field.ID) error
+	}
+
+	// Repo 组织层级仓库，父级以 Organization.ParentIds 为准，闭包表由写操作同步维护
+	Repo struct {
+		store IStore
+	}
+)
+
+func NewRepo(store IStore) *Repo {
+	return &Repo{store: store}
+}
+
+// Insert 新增组织的层级记录
+func (r *Repo) Insert(ctx context.Context, org *model.Organization) error {
+	parentIds, err := r.checkParents(ctx, org.ID, org.ParentIds)
+	if err != nil {
+		return err
+	}
+	return r.rebuild(ctx, []field.ID{org.ID}, map[field.ID][]field.ID{org.ID: parentIds})
+}
+
+// Move 变更父级(组织及其全部后代的祖先记录重新计算)
+func (r *Repo) Move(ctx context.Context, id field.ID, parentIds []field.ID) error {
+	parentIds, err := r.checkParents(ctx, id, parentIds)
+	if err != nil {
+		return err
+	}
+	return r.rebuild(ctx, []field.ID{id}, map[field.ID][]field.ID{id: parentIds})
+}
+
+// Delete 删除组织的层级记录，子级不再以其为父级(没有其他父级时成为顶级组织)
+func (r *Repo) Delete(ctx context.Context, id field.ID) error {
+	children, err := r.Children(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = r.store.Remove(ctx, id); err != nil {
+		return errs.Internal(err)
+	}
+	return r.rebuild(ctx, children, nil)
+}
+
+// Ancestors 祖先(不含自身)，由近到远
+func (r *Repo) Ancestors(ctx context.Context, id field.ID) ([]*model.OrgClosure, error) {
+	rows, err := r.store.Ancestors(ctx, id)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+	return rows, nil
+}
+
+// Descendants 后代(不含自身)，由近到远，depth<=0 时不限深度
+func (r *Repo) Descendants(ctx context.Context, id field.ID, depth int) ([]*model.OrgClosure, error) {
+	rows, err := r.store.Descendants(ctx, id, depth)
+	if err != nil {
+		return nil, errs.Internal(err)
+	}
+	return rows, nil
+}
+
+// Parents 直接父级
+func (r *Repo) Parents(ctx context.Context, id field.ID) ([]field.ID, error) {
+	rows, err := r.Ancestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows = slices.DeleteFunc(rows, func(c *model.OrgClosure) bool { return c.Depth != 1 })
+	return closureIds(rows, func(c *model.OrgClosure) field.ID { return c.AncestorId }), nil
+}
+
+// Children 直接子级
+func (r *Repo) Children(ctx context.Context, id field.ID) ([]field.ID, error) {
+	rows, err := r.Descendants(ctx, id, 1)
+	if err != nil {
+		return nil, err
+	}
+	return closureIds(rows, func(c *model.OrgClosure) field.ID { return c.DescendantId }), nil
+}
+
+// IsAncestor ancestorId 是否为 id 的祖先(不含自身)
+func (r *Repo) IsAncestor(ctx context.Context, ancestorId, id field.ID) (bool, error) {
+	rows, err := r.Ancestors(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(rows, func(c *model.OrgClosure) bool { return c.AncestorId == ancestorId }), nil
+}
+
+// IsDescendant descendantId 是否为 id 的后代(不含自身)
+func (r *Repo) IsDescendant(ctx context.Context, descendantId, id field.ID) (bool, error) {
+	return r.IsAncestor(ctx, id, descendantId)
+}
+
+// checkParents 父级去重，父级不存在时返回 NotFound，父级是自身或后代时返回 ErrCycle
+func (r *Repo) checkParents(ctx context.Context, id field.ID, parentIds []field.ID) ([]field.ID, error) {
+	parentIds = slices.Clone(parentIds)
+	slices.Sort(parentIds)
+	parentIds = slices.Compact(parentIds)
+	for _, parentId := range parentIds {
+		if parentId == id {
+			return nil, errs.FailedPrecondition(MsgCycle, id, parentId)
+		}
+		exists, err := r.store.Exists(ctx, parentId)
+		if err != nil {
+			return nil, errs.Internal(err)
+		}
+		if !exists {
+			return nil, errs.NotFound(MsgParentNotFound, id, parentId)
+		}
+		cycle, err := r.IsAncestor(ctx, id, parentId)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, errs.FailedPrecondition(MsgCycle, id, parentId)
+		}
+	}
+	return parentIds, nil
+}
+
+// rebuild 重新计算 roots 及其全部后代的祖先记录
+// parents 覆盖指定组织的直接父级，其余组织的父级从闭包表读取(深度为1的记录)
+func (r *Repo) rebuild(ctx context.Context, roots []field.ID, parents map[field.ID][]field.ID) error {
+	// -- 受影响的组织(子树并集) --
+	var nodes []field.ID
+	affected := map[field.ID]bool{}
+	for _, root := range roots {
+		rows, err := r.Descendants(ctx, root, 0)
+		if err != nil {
+			return err
+		}
+		for _, id := range append([]field.ID{root}, closureIds(rows, func(c *model.OrgClosure) field.ID { return c.DescendantId })...) {
+			if !affected[id] {
+				affected[id] = true
+				nodes = append(nodes, id)
+			}
+		}
+	}
+
+	// -- 直接父级 --
+	nodeParents := make(map[field.ID][]field.ID, len(nodes))
+	for _, id := range nodes {
+		if parentIds, ok := parents[id]; ok {
+			nodeParents[id] = parentIds
+			continue
+		}
+		parentIds, err := r.Parents(ctx, id)
+		if err != nil {
+			return err
+		}
+		nodeParents[id] = parentIds
+	}
+
+	// -- 按父级在前排序后逐个计算(子树外的父级不受影响，直接读取) --
+	ancestors := make(map[field.ID]map[field.ID]int, len(nodes))
+	for _, id := range topoSort(nodes, nodeParents, affected) {
+		depths := map[field.ID]int{}
+		for _, parentId := range nodeParents[id] {
+			minDepth(depths, parentId, 1)
+			if affected[parentId] {
+				for ancestorId, depth := range ancestors[parentId] {
+					minDepth(depths, ancestorId, depth+1)
+				}
+				continue
+			}
+			rows, err := r.Ancestors(ctx, parentId)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				minDepth(depths, row.AncestorId, row.Depth+1)
+			}
+		}
+		ancestors[id] = depths
+
+		rows := []*model.OrgClosure{model.NewOrgClosure(id, id, 0)}
+		for ancestorId, depth := range depths {
+			rows = append(rows, model.NewOrgClosure(ancestorId, id, depth))
+		}
+		sortClosures(rows, func(c *model.OrgClosure) field.ID { return c.AncestorId })
+		if err := r.store.Replace(ctx, id, rows); err != nil {
+			return errs.Internal(err)
+		}
+	}
+	return nil
+}
+
+// topoSort 子树内父级在前(父级已通过 checkParents 保证无环)
+func topoSort(nodes []field.ID, parents map[field.ID][]field.ID, affected map[field.ID]bool) []field.ID {
+	pending := make(map[field.ID]int, len(nodes))
+	children := map[field.ID][]field.ID{}
+	for _, id := range nodes {
+		for _, parentId := range parents[id] {
+			if affected[parentId] {
+				pending[id]++
+				children[parentId] = append(children[parentId], id)
+			}
+		}
+	}
+	sorted := make([]field.ID, 0, len(nodes))
+	for _, id := range nodes {
+		if pending[id] == 0 {
+			sorted = append(sorted, id)
+		}
+	}
+	for i := 0; i < len(sorted); i++ {
+		for _, child := range children[sorted[i]] {
+			if pending[child]--; pending[child] == 0 {
+				sorted = append(sorted, child)
+			}
+		}
+	}
+	return sorted
+}
+
+// minDepth 多条路径时保留最短深度
+func minDepth(depths map[field.ID]int, id field.ID, depth int) {
+	if old, ok := depths[id]; !ok || depth < old {
+		depths[id] = depth
+	}
+}
+
+// sortClosures 按深度排序，深度相同按 id 排序
+func sortClosures(rows []*model.OrgClosure, id func(*model.OrgClosure) field.ID) {
+	slices.SortFunc(rows, func(a, b *model.OrgClosure) int {
+		if a.Depth != b.Depth {
+			return a.Depth - b.Depth
+		}
+		return cmp.Compare(id(a), id(b))
+	})
+}
+
+// closureIds 记录 -> 祖先/后代ID
+func closureIds(rows []*model.OrgClosure, id func(*model.OrgClosure) field.ID) []field.ID {
+	ids := make([]field.ID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, id(row))
+	}
+	return ids
+}
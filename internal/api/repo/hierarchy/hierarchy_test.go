@@ -0,0 +1,124 @@
+package hierarchy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"katydid-mp-account/internal/api/model"
+	"katydid-mp-account/pkg/errs"
+	"katydid-mp-account/pkg/field"
+	"slices"
+	"testing"
+)
+
+// newTestRepo 1 -> 2 -> 3, 4 的父级为 1 和 3
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+	repo := NewRepo(NewMemStore())
+	for _, node := range []struct {
+		id        field.ID
+		parentIds []field.ID
+	}{{1, nil}, {2, []field.ID{1}}, {3, []field.ID{2}}, {4, []field.ID{1, 3}}} {
+		org := model.NewOrganization(1, node.parentIds, false, model.OrgKindGroup, model.OrgBecomeDirect, "org", "", nil)
+		org.ID = node.id
+		if err := repo.Insert(context.Background(), org); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// closureStr 记录 -> "祖先>后代:深度"
+func closureStr(rows []*model.OrgClosure) []string {
+	strs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		strs = append(strs, fmt.Sprintf("%d>%d:%d", row.AncestorId, row.DescendantId, row.Depth))
+	}
+	return strs
+}
+
+func TestRepoDescendants(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	tests := []struct {
+		id    field.ID
+		depth int
+		want  []string
+	}{
+		{1, 0, []string{"1>2:1", "1>4:1", "1>3:2"}},
+		{1, 1, []string{"1>2:1", "1>4:1"}},
+		{2, 0, []string{"2>3:1", "2>4:2"}},
+		{4, 0, []string{}},
+	}
+	for _, tt := range tests {
+		rows, err := repo.Descendants(ctx, tt.id, tt.depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := closureStr(rows); !slices.Equal(got, tt.want) {
+			t.Errorf("Descendants(%d, %d) = %v, want %v", tt.id, tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestRepoMove(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// 后代作为父级
+	if err := repo.Move(ctx, 2, []field.ID{3}); !errors.Is(err, ErrCycle) {
+		t.Fatalf("Move to descendant err = %v, want ErrCycle", err)
+	}
+
+	// 2 成为顶级组织: 1 只剩直接子级 4
+	if err := repo.Move(ctx, 2, nil); err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := repo.Descendants(ctx, 1, 0)
+	if got, want := closureStr(rows), []string{"1>4:1"}; !slices.Equal(got, want) {
+		t.Fatalf("Descendants(1) after move = %v, want %v", got, want)
+	}
+	rows, _ = repo.Ancestors(ctx, 4)
+	if got, want := closureStr(rows), []string{"1>4:1", "3>4:1", "2>4:2"}; !slices.Equal(got, want) {
+		t.Fatalf("Ancestors(4) after move = %v, want %v", got, want)
+	}
+}
+
+func TestRepoDelete(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// 3 的子级 4 仍以 1 为父级
+	if err := repo.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := repo.Descendants(ctx, 2, 0)
+	if len(rows) != 0 {
+		t.Fatalf("Descendants(2) after delete = %v, want none", closureStr(rows))
+	}
+	rows, _ = repo.Ancestors(ctx, 4)
+	if got, want := closureStr(rows), []string{"1>4:1"}; !slices.Equal(got, want) {
+		t.Fatalf("Ancestors(4) after delete = %v, want %v", got, want)
+	}
+}
+
+func TestRepoParentNotFound(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	org := model.NewOrganization(1, []field.ID{1, 9}, false, model.OrgKindGroup, model.OrgBecomeDirect, "org", "", nil)
+	org.ID = 5
+	if err := repo.Insert(ctx, org); !errors.Is(err, errs.NotFound(MsgParentNotFound)) {
+		t.Fatalf("Insert with missing parent err = %v, want NotFound", err)
+	}
+	if err := repo.Move(ctx, 2, []field.ID{9}); !errors.Is(err, errs.NotFound(MsgParentNotFound)) {
+		t.Fatalf("Move to missing parent err = %v, want NotFound", err)
+	}
+	rows, _ := repo.Descendants(ctx, 1, 0)
+	if got, want := closureStr(rows), []string{"1>2:1", "1>4:1", "1>3:2"}; !slices.Equal(got, want) {
+		t.Fatalf("Descendants(1) = %v, want %v", got, want)
+	}
+	if rows, _ = repo.Ancestors(ctx, 5); len(rows) != 0 {
+		t.Fatalf("Ancestors(5) = %v, want none", closureStr(rows))
+	}
+}
@@ -0,0 +1,126 @@
+package hierarchy
+
+import (
+	"context"
+	"katydid-mp-account/internal/api/model"
+	"katydid-mp-account/pkg/field"
+	"maps"
+	"sync"
+)
+
+// MemStore 内存闭包表(单机部署/测试)，同时按后代和祖先索引
+type MemStore struct {
+	mu   sync.RWMutex
+	rows map[field.ID]map[field.ID]int // 后代 -> 祖先 -> 深度(含自身)
+	desc map[field.ID]map[field.ID]int // 祖先 -> 后代 -> 深度(含自身)
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{
+		rows: make(map[field.ID]map[field.ID]int),
+		desc: make(map[field.ID]map[field.ID]int),
+	}
+}
+
+// Snapshot 当前记录的拷贝(单机部署时事务回滚用，见 Restore)
+func (s *MemStore) Snapshot() *MemStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &MemStore{rows: cloneIndex(s.rows), desc: cloneIndex(s.desc)}
+}
+
+// Restore 恢复为快照的记录
+func (s *MemStore) Restore(snapshot *MemStore) {
+	snapshot.mu.RLock()
+	rows, desc := cloneIndex(snapshot.rows), cloneIndex(snapshot.desc)
+	snapshot.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows, s.desc = rows, desc
+}
+
+func (s *MemStore) Exists(_ context.Context, id field.ID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.rows[id][id]
+	return ok, nil
+}
+
+func (s *MemStore) Ancestors(_ context.Context, id field.ID) ([]*model.OrgClosure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := make([]*model.OrgClosure, 0, len(s.rows[id]))
+	for ancestorId, depth := range s.rows[id] {
+		if depth > 0 {
+			rows = append(rows, model.NewOrgClosure(ancestorId, id, depth))
+		}
+	}
+	sortClosures(rows, func(c *model.OrgClosure) field.ID { return c.AncestorId })
+	return rows, nil
+}
+
+func (s *MemStore) Descendants(_ context.Context, id field.ID, depth int) ([]*model.OrgClosure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var rows []*model.OrgClosure
+	for descendantId, d := range s.desc[id] {
+		if d > 0 && (depth <= 0 || d <= depth) {
+			rows = append(rows, model.NewOrgClosure(id, descendantId, d))
+		}
+	}
+	sortClosures(rows, func(c *model.OrgClosure) field.ID { return c.DescendantId })
+	return rows, nil
+}
+
+func (s *MemStore) Replace(_ context.Context, id field.ID, rows []*model.OrgClosure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unlink(id)
+	ancestors := make(map[field.ID]int, len(rows))
+	for _, row := range rows {
+		ancestors[row.AncestorId] = row.Depth
+		if s.desc[row.AncestorId] == nil {
+			s.desc[row.AncestorId] = make(map[field.ID]int)
+		}
+		s.desc[row.AncestorId][id] = row.Depth
+	}
+	s.rows[id] = ancestors
+	return nil
+}
+
+func (s *MemStore) Remove(_ context.Context, id field.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unlink(id)
+	delete(s.rows, id)
+	for descendantId := range s.desc[id] {
+		delete(s.rows[descendantId], id)
+	}
+	delete(s.desc, id)
+	return nil
+}
+
+// unlink 从祖先索引中删除组织作为后代的记录
+func (s *MemStore) unlink(id field.ID) {
+	for ancestorId := range s.rows[id] {
+		if delete(s.desc[ancestorId], id); len(s.desc[ancestorId]) == 0 {
+			delete(s.desc, ancestorId)
+		}
+	}
+}
+
+// cloneIndex 深拷贝索引
+func cloneIndex(index map[field.ID]map[field.ID]int) map[field.ID]map[field.ID]int {
+	clone := make(map[field.ID]map[field.ID]int, len(index))
+	for id, depths := range index {
+		clone[id] = maps.Clone(depths)
+	}
+	return clone
+}
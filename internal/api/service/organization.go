@@ -0,0 +1,250 @@
+// Package service 业务流程(跨仓库的事务操作)
+package service
+
+import (
+	"context"
+	"katydid-mp-account/internal/api/model"
+	"katydid-mp-account/pkg/errs"
+	"katydid-mp-account/pkg/field"
+	"katydid-mp-account/pkg/valid"
+	"slices"
+)
+
+// 错误消息键
+const (
+	MsgOrgMergeSelf       = "err_org_merge_self"       // 不能合并到自身
+	MsgOrgMergeDescendant = "err_org_merge_descendant" // 不能合并到自身的后代 {组织, 目标组织}
+	MsgOrgNotParent       = "err_org_not_parent"       // 不是组织的父级 {组织, 父级}
+)
+
+func init() {
+	errs.RegisterMsg(MsgOrgMergeSelf, MsgOrgMergeDescendant, MsgOrgNotParent)
+}
+
+// 依赖(由仓库实现)，仓库从 ctx 获取 ITxRunner 开启的事务
+type (
+	// ITxRunner 事务，fn 返回错误时回滚
+	ITxRunner interface {
+		InTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	// IHierarchy 组织层级(见 hierarchy.Repo)
+	IHierarchy interface {
+		Move(ctx context.Context, id field.ID, parentIds []field.ID) error
+		Delete(ctx context.Context, id field.ID) error
+		Children(ctx context.Context, id field.ID) ([]field.ID, error)
+		IsDescendant(ctx context.Context, descendantId, id field.ID) (bool, error)
+	}
+
+	IOrgRepo interface {
+		Get(ctx context.Context, id field.ID) (*model.Organization, error)          // 不存在返回 errs.CodeNotFound
+		GetMany(ctx context.Context, ids []field.ID) ([]*model.Organization, error) // 不存在的组织不返回
+		Update(ctx context.Context, org *model.Organization) error                  // 保存全部字段
+		Delete(ctx context.Context, id field.ID) error                              // 软删除(保留审计记录中的引用)
+	}
+	IMemberRepo interface {
+		Reassign(ctx context.Context, srcOrgId, dstOrgId field.ID) (int64, error) // 成员转移，已在目标组织的成员保留目标组织的任职
+	}
+	IRoleRepo interface {
+		Reassign(ctx context.Context, srcOrgId, dstOrgId field.ID) (int64, error) // 角色转移，同名角色合并授权
+	}
+	IOrgAuditRepo interface {
+		Insert(ctx context.Context, audit *model.OrgAudit) error
+	}
+)
+
+// OrgService 组织结构调整(移动/合并)，父级(ParentIds)和层级(闭包表)在同一事务中更新
+type OrgService struct {
+	tx        ITxRunner
+	orgs      IOrgRepo
+	hierarchy IHierarchy
+	members   IMemberRepo
+	roles     IRoleRepo
+	audits    IOrgAuditRepo
+}
+
+func NewOrgService(
+	tx ITxRunner, orgs IOrgRepo, hierarchy IHierarchy,
+	members IMemberRepo, roles IRoleRepo, audits IOrgAuditRepo,
+) *OrgService {
+	return &OrgService{tx: tx, orgs: orgs, hierarchy: hierarchy, members: members, roles: roles, audits: audits}
+}
+
+// MoveOrganization 移动组织: 父级 oldParent 替换为 newParent，其他父级保留(多父级组织)
+// oldParent 为0时添加父级，newParent 为0时移除父级(没有其他父级时成为顶级组织)
+// 验证类型包含关系(父级能否包含自身)和循环(父级不能是自身或后代)
+func (s *OrgService) MoveOrganization(ctx context.Context, id, oldParent, newParent field.ID) error {
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		org, err := s.orgs.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if oldParent != 0 && !slices.Contains(org.ParentIds, oldParent) {
+			return errs.FailedPrecondition(MsgOrgNotParent, id, oldParent)
+		}
+		before := org.ParentIds
+		parentIds := slices.DeleteFunc(slices.Clone(before), func(parentId field.ID) bool {
+			return parentId == oldParent || parentId == newParent
+		})
+		if newParent != 0 {
+			parentIds = append(parentIds, newParent)
+		}
+
+		// -- 类型包含关系 --
+		parentKinds, err := s.kinds(ctx, parentIds)
+		if err != nil {
+			return err
+		}
+		org.ParentIds = parentIds
+		org.SetRelKinds(&model.OrgRelKinds{Parents: parentKinds})
+		if e := errs.FromMsgErrs(valid.CheckFields(org, model.OrgSceneMove, "parentIds")); e != nil {
+			return e
+		}
+
+		// -- 层级(含循环检查) --
+		if err = s.hierarchy.Move(ctx, id, parentIds); err != nil {
+			return err
+		}
+		if err = s.orgs.Update(ctx, org); err != nil {
+			return err
+		}
+		return s.audits.Insert(ctx, model.NewOrgAudit(id, model.OrgAuditMove, newParent, before, parentIds))
+	})
+}
+
+// MergeOrganizations 合并组织 src 到 dst: 子级组织改挂到 dst 下，成员/角色转移到 dst，最后删除 src
+// 验证 dst 能否包含 src 的子级类型，dst 是 src 的后代时子级改挂会形成循环(返回 errs.CodeConflict)
+func (s *OrgService) MergeOrganizations(ctx context.Context, src, dst field.ID) error {
+	if src == dst {
+		return errs.FailedPrecondition(MsgOrgMergeSelf, src)
+	}
+	if err := s.checkMergeTarget(ctx, src, dst); err != nil {
+		return err // 事务外快速失败
+	}
+	return s.tx.InTx(ctx, func(ctx context.Context) error {
+		// 事务内再次检查(事务外检查后可能有并发的移动)
+		if err := s.checkMergeTarget(ctx, src, dst); err != nil {
+			return err
+		}
+		srcOrg, err := s.orgs.Get(ctx, src)
+		if err != nil {
+			return err
+		}
+		dstOrg, err := s.orgs.Get(ctx, dst)
+		if err != nil {
+			return err
+		}
+
+		// -- 类型包含关系(dst 能否包含 src 的子级) --
+		childIds, err := s.hierarchy.Children(ctx, src)
+		if err != nil {
+			return err
+		}
+		children, err := s.load(ctx, childIds)
+		if err != nil {
+			return err
+		}
+		childKinds := make(map[field.ID]uint8, len(children))
+		for _, child := range children {
+			childKinds[child.ID] = child.Kind
+		}
+		dstOrg.SetRelKinds(&model.OrgRelKinds{Children: childKinds})
+		if e := errs.FromMsgErrs(valid.CheckFields(dstOrg, model.OrgSceneMerge, "kind")); e != nil {
+			return e
+		}
+		audit := model.NewOrgAudit(src, model.OrgAuditMerge, dst, srcOrg.ParentIds, nil)
+		audit.SetSrc(srcOrg)
+		audit.SetDst(dstOrg)
+
+		// -- 子级改挂(含循环检查) --
+		for _, child := range children {
+			parentIds := replaceParent(child.ParentIds, src, dst)
+			if err = s.hierarchy.Move(ctx, child.ID, parentIds); err != nil {
+				return err
+			}
+			child.ParentIds = parentIds
+			if err = s.orgs.Update(ctx, child); err != nil {
+				return err
+			}
+		}
+
+		// -- 成员/角色 --
+		members, err := s.members.Reassign(ctx, src, dst)
+		if err != nil {
+			return err
+		}
+		roles, err := s.roles.Reassign(ctx, src, dst)
+		if err != nil {
+			return err
+		}
+
+		// -- 删除 src(审计记录保留) --
+		if err = s.hierarchy.Delete(ctx, src); err != nil {
+			return err
+		}
+		if err = s.orgs.Delete(ctx, src); err != nil {
+			return err
+		}
+		moved := make([]int64, 0, len(childIds))
+		for _, childId := range childIds {
+			moved = append(moved, childId.Value())
+		}
+		audit.SetChildren(&moved)
+		audit.SetMembers(&members)
+		audit.SetRoles(&roles)
+		return s.audits.Insert(ctx, audit)
+	})
+}
+
+// checkMergeTarget dst 是 src 的后代时返回 errs.CodeConflict
+func (s *OrgService) checkMergeTarget(ctx context.Context, src, dst field.ID) error {
+	descendant, err := s.hierarchy.IsDescendant(ctx, dst, src)
+	if err != nil {
+		return err
+	}
+	if descendant {
+		return errs.Conflict(MsgOrgMergeDescendant, src, dst)
+	}
+	return nil
+}
+
+// load 批量获取组织，有不存在的组织时返回 errs.CodeNotFound
+func (s *OrgService) load(ctx context.Context, ids []field.ID) ([]*model.Organization, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	orgs, err := s.orgs.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(orgs, func(o *model.Organization) bool { return o.ID == id }) {
+			return nil, errs.NotFound("", id)
+		}
+	}
+	return orgs, nil
+}
+
+// kinds 组织 -> 类型
+func (s *OrgService) kinds(ctx context.Context, ids []field.ID) (map[field.ID]uint8, error) {
+	orgs, err := s.load(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	kinds := make(map[field.ID]uint8, len(orgs))
+	for _, org := range orgs {
+		kinds[org.ID] = org.Kind
+	}
+	return kinds, nil
+}
+
+// replaceParent 父级 src 替换为 dst(已有 dst 时去重)
+func replaceParent(parentIds []field.ID, src, dst field.ID) []field.ID {
+	replaced := make([]field.ID, 0, len(parentIds)+1)
+	for _, parentId := range parentIds {
+		if parentId != src && parentId != dst {
+			replaced = append(replaced, parentId)
+		}
+	}
+	return append(replaced, dst)
+}
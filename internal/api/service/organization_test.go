@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"katydid-mp-account/internal/api/model"
+	"katydid-mp-account/internal/api/repo/hierarchy"
+	"katydid-mp-account/pkg/errs"
+	"katydid-mp-account/pkg/field"
+	"maps"
+	"slices"
+	"testing"
+)
+
+type (
+	// fakeTx 事务失败时恢复闭包表/组织/审计记录
+	fakeTx struct {
+		store  *hierarchy.MemStore
+		orgs   *fakeOrgRepo
+		audits *fakeAuditRepo
+		calls  int
+		before func() // 事务开始前执行(模拟并发提交的其他事务)
+	}
+
+	// fakeOrgRepo 读写都拷贝，修改只在 Update 后可见
+	fakeOrgRepo struct {
+		orgs map[field.ID]*model.Organization
+	}
+
+	fakeReassignRepo struct {
+		n   int64
+		err error
+	}
+
+	fakeAuditRepo struct {
+		audits []*model.OrgAudit
+	}
+)
+
+func (t *fakeTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.calls++
+	if t.before != nil {
+		t.before()
+	}
+	store, orgs, audits := t.store.Snapshot(), maps.Clone(t.orgs.orgs), len(t.audits.audits)
+	if err := fn(ctx); err != nil {
+		t.store.Restore(store)
+		t.orgs.orgs, t.audits.audits = orgs, t.audits.audits[:audits]
+		return err
+	}
+	return nil
+}
+
+func copyOrg(org *model.Organization) *model.Organization {
+	c := *org
+	c.ParentIds = slices.Clone(org.ParentIds)
+	return &c
+}
+
+func (r *fakeOrgRepo) Get(_ context.Context, id field.ID) (*model.Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, errs.NotFound("", id)
+	}
+	return copyOrg(org), nil
+}
+
+func (r *fakeOrgRepo) GetMany(_ context.Context, ids []field.ID) ([]*model.Organization, error) {
+	var orgs []*model.Organization
+	for _, id := range ids {
+		if org, ok := r.orgs[id]; ok {
+			orgs = append(orgs, copyOrg(org))
+		}
+	}
+	return orgs, nil
+}
+
+func (r *fakeOrgRepo) Update(_ context.Context, org *model.Organization) error {
+	r.orgs[org.ID] = copyOrg(org)
+	return nil
+}
+
+func (r *fakeOrgRepo) Delete(_ context.Context, id field.ID) error {
+	delete(r.orgs, id)
+	return nil
+}
+
+func (r *fakeReassignRepo) Reassign(context.Context, field.ID, field.ID) (int64, error) {
+	return r.n, r.err
+}
+
+func (r *fakeAuditRepo) Insert(_ context.Context, audit *model.OrgAudit) error {
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+type testOrgService struct {
+	*OrgService
+	tx        *fakeTx
+	orgs      *fakeOrgRepo
+	hierarchy *hierarchy.Repo
+	roles     *fakeReassignRepo
+	audits    *fakeAuditRepo
+}
+
+// newTestOrgService 1(集团) -> 2(公司) -> 3(公司)，4(团队) 的父级为 2 和 5(集团)
+func newTestOrgService(t *testing.T) *testOrgService {
+	t.Helper()
+	store := hierarchy.NewMemStore()
+	s := &testOrgService{
+		orgs:      &fakeOrgRepo{orgs: map[field.ID]*model.Organization{}},
+		hierarchy: hierarchy.NewRepo(store),
+		roles:     &fakeReassignRepo{n: 1},
+		audits:    &fakeAuditRepo{},
+	}
+	s.tx = &fakeTx{store: store, orgs: s.orgs, audits: s.audits}
+	s.OrgService = NewOrgService(s.tx, s.orgs, s.hierarchy, &fakeReassignRepo{n: 2}, s.roles, s.audits)
+
+	for _, node := range []struct {
+		id        field.ID
+		kind      uint8
+		parentIds []field.ID
+	}{
+		{1, model.OrgKindGroup, nil},
+		{5, model.OrgKindGroup, nil},
+		{2, model.OrgKindCompany, []field.ID{1}},
+		{3, model.OrgKindCompany, []field.ID{2}},
+		{4, model.OrgKindTeam, []field.ID{2, 5}},
+	} {
+		org := model.NewOrganization(1, node.parentIds, false, node.kind, model.OrgBecomeDirect, "org", "", nil)
+		org.ID = node.id
+		if err := s.hierarchy.Insert(context.Background(), org); err != nil {
+			t.Fatal(err)
+		}
+		s.orgs.orgs[org.ID] = org
+	}
+	return s
+}
+
+// assertParents 组织的父级(ParentIds 和闭包表一致)
+func (s *testOrgService) assertParents(t *testing.T, id field.ID, want ...field.ID) {
+	t.Helper()
+	parents, err := s.hierarchy.Parents(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	org := s.orgs.orgs[id]
+	if org == nil {
+		t.Fatalf("org %d not found", id)
+	}
+	got := slices.Sorted(slices.Values(org.ParentIds))
+	if !slices.Equal(got, want) || !slices.Equal(parents, want) {
+		t.Fatalf("org %d parents = %v, closure parents = %v, want %v", id, got, parents, want)
+	}
+}
+
+func TestMoveOrganization(t *testing.T) {
+	s := newTestOrgService(t)
+	ctx := context.Background()
+
+	// 多父级组织只替换指定的父级
+	if err := s.MoveOrganization(ctx, 4, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.assertParents(t, 4, 1, 5)
+	audit := s.audits.audits[0]
+	if audit.Action != model.OrgAuditMove || !slices.Equal(audit.Before, []field.ID{2, 5}) || !slices.Equal(audit.After, []field.ID{5, 1}) {
+		t.Fatalf("audit = %+v", audit)
+	}
+
+	// 移除父级
+	if err := s.MoveOrganization(ctx, 4, 5, 0); err != nil {
+		t.Fatal(err)
+	}
+	s.assertParents(t, 4, 1)
+}
+
+func TestMoveOrganizationRejected(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name                     string
+		id, oldParent, newParent field.ID
+		want                     error
+		wantParents              []field.ID
+	}{
+		{"not parent", 4, 3, 1, errs.FailedPrecondition(MsgOrgNotParent), []field.ID{2, 5}},
+		{"cycle", 2, 1, 3, hierarchy.ErrCycle, []field.ID{1}},
+		{"kind", 1, 0, 3, errs.New(errs.CodeValidation, ""), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestOrgService(t)
+			if err := s.MoveOrganization(ctx, tt.id, tt.oldParent, tt.newParent); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			s.assertParents(t, tt.id, tt.wantParents...)
+			if len(s.audits.audits) != 0 {
+				t.Fatalf("audits = %v, want none", s.audits.audits)
+			}
+		})
+	}
+}
+
+func TestMergeOrganizations(t *testing.T) {
+	s := newTestOrgService(t)
+	ctx := context.Background()
+
+	if err := s.MergeOrganizations(ctx, 2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.orgs.orgs[2]; ok {
+		t.Fatal("src not deleted")
+	}
+	s.assertParents(t, 3, 5)
+	s.assertParents(t, 4, 5)
+	children, _ := s.hierarchy.Children(ctx, 1)
+	if len(children) != 0 {
+		t.Fatalf("children of src parent = %v, want none", children)
+	}
+
+	audit := s.audits.audits[0]
+	if audit.Action != model.OrgAuditMerge || !slices.Equal(audit.Before, []field.ID{1}) ||
+		!slices.Equal(audit.GetChildren(), []int64{3, 4}) || audit.GetMembers() != 2 || audit.GetRoles() != 1 {
+		t.Fatalf("audit = %+v", audit)
+	}
+	if src, dst := audit.GetSrc(), audit.GetDst(); src["id"] != int64(2) || src["kind"] != model.OrgKindCompany || dst["id"] != int64(5) {
+		t.Fatalf("audit snapshots src = %v, dst = %v", src, dst)
+	}
+}
+
+func TestMergeOrganizationsIntoDescendant(t *testing.T) {
+	s := newTestOrgService(t)
+	err := s.MergeOrganizations(context.Background(), 2, 3)
+	if !errors.Is(err, errs.Conflict(MsgOrgMergeDescendant)) {
+		t.Fatalf("err = %v, want conflict", err)
+	}
+	if s.tx.calls != 0 {
+		t.Fatal("transaction started for merge into descendant")
+	}
+}
+
+func TestMergeOrganizationsConcurrentMove(t *testing.T) {
+	s := newTestOrgService(t)
+	ctx := context.Background()
+
+	// 事务外检查通过后，5 被并发移动到 3 下(成为 2 的后代)
+	s.tx.before = func() {
+		if err := s.hierarchy.Move(ctx, 5, []field.ID{3}); err != nil {
+			t.Fatal(err)
+		}
+		s.orgs.orgs[5].ParentIds = []field.ID{3}
+	}
+	err := s.MergeOrganizations(ctx, 2, 5)
+	if !errors.Is(err, errs.Conflict(MsgOrgMergeDescendant)) {
+		t.Fatalf("err = %v, want conflict", err)
+	}
+	s.assertParents(t, 2, 1)
+	s.assertParents(t, 3, 2)
+	s.assertParents(t, 5, 3)
+	if len(s.audits.audits) != 0 {
+		t.Fatalf("audits = %v, want none", s.audits.audits)
+	}
+}
+
+func TestMergeOrganizationsRollback(t *testing.T) {
+	s := newTestOrgService(t)
+	s.roles.err = errors.New("roles down")
+
+	if err := s.MergeOrganizations(context.Background(), 2, 5); !errors.Is(err, s.roles.err) {
+		t.Fatalf("err = %v, want %v", err, s.roles.err)
+	}
+	s.assertParents(t, 2, 1)
+	s.assertParents(t, 3, 2)
+	s.assertParents(t, 4, 2, 5)
+	if len(s.audits.audits) != 0 {
+		t.Fatalf("audits = %v, want none", s.audits.audits)
+	}
+}
@@ -0,0 +1,21 @@
+package locales
+
+import (
+	"embed"
+	"katydid-mp-account/pkg/i18n"
+)
+
+// DefaultLang 默认语言
+const DefaultLang = "zh"
+
+//go:embed *.json
+var files embed.FS
+
+// NewBundle 加载内置消息目录
+func NewBundle() (*i18n.Bundle, error) {
+	bundle := i18n.NewBundle(DefaultLang)
+	if err := bundle.LoadFS(files, "."); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
@@ -15,13 +15,13 @@ type (
 
 		CreateAt time.Time  `json:"createAt" gorm:"autoCreateTime:milli;comment:创建时间"`
 		UpdateAt time.Time  `json:"updateAt" gorm:"autoUpdateTime:milli;comment:更新时间"`
-		DeleteAt *time.Time `json:"deleteAt;comment:删除时间"` // 删除人可以在 Extra 中设置
+		DeleteAt *time.Time `json:"deleteAt" gorm:"comment:删除时间"` // 删除人可以在 Extra 中设置
 
 		// id
 		// index
 		// required
 
-		Extra field.KMap `json:"extra" gorm:"serializer:json;comment:额外信息"` // (!索引+!必需)
+		Extra field.KMap `json:"extra" gorm:"serializer:json;comment:额外信息"` // (!索引+!必需) 敏感键入库加密
 	}
 )
 
@@ -34,6 +34,17 @@ func NewBase(id field.ID) Base {
 	}
 }
 
+// Clone 深拷贝(Extra等可变数据不共享)，用于在协程间传递缓存实体
+func (b *Base) Clone() Base {
+	clone := *b
+	if b.DeleteAt != nil {
+		deleteAt := *b.DeleteAt
+		clone.DeleteAt = &deleteAt
+	}
+	clone.Extra = b.Extra.Clone()
+	return clone
+}
+
 const (
 	extKeyAdminNote = "adminNote" // 管理员备注
 )
@@ -46,17 +57,30 @@ func (b *Base) SetAdminNote(adminNote *string) {
 	b.Extra.SetString(extKeyAdminNote, adminNote)
 }
 
+// ViewRules 响应视图规则
+func (b *Base) ViewRules() valid.ViewRules {
+	return valid.ViewRules{
+		valid.SceneAll: valid.ViewRule{
+			Fields: map[valid.FieldName]valid.Audience{
+				"DeleteAt": valid.AudienceAdmin,
+			},
+			Extra: map[string]valid.Audience{
+				extKeyAdminNote: valid.AudienceAdmin,
+			},
+		},
+	}
+}
+
 // ValidFieldRules 字段验证规则
 func (b *Base) ValidFieldRules() valid.FieldValidRules {
 	return valid.FieldValidRules{
-		valid.SceneAll:    valid.FieldValidRule{},
-		valid.SceneBind:   valid.FieldValidRule{},
-		valid.SceneSave:   valid.FieldValidRule{},
-		valid.SceneInsert: valid.FieldValidRule{},
-		valid.SceneUpdate: valid.FieldValidRule{},
-		valid.SceneQuery:  valid.FieldValidRule{},
-		valid.SceneReturn: valid.FieldValidRule{},
-		valid.SceneCustom: valid.FieldValidRule{},
+		valid.SceneAll:  valid.FieldValidRule{},
+		valid.SceneBind: valid.FieldValidRule{},
+		valid.SceneSave: valid.FieldValidRule{},
+		valid.SceneAdd:  valid.FieldValidRule{},
+		valid.SceneUpd:  valid.FieldValidRule{},
+		valid.SceneGet:  valid.FieldValidRule{},
+		valid.SceneRes:  valid.FieldValidRule{},
 	}
 }
 
@@ -75,13 +99,12 @@ func (b *Base) ValidExtraRules() (field.KMap, valid.ExtraValidRules) {
 				},
 			},
 		},
-		valid.SceneBind:   map[valid.Tag]valid.ExtraValidRuleInfo{},
-		valid.SceneSave:   map[valid.Tag]valid.ExtraValidRuleInfo{},
-		valid.SceneInsert: map[valid.Tag]valid.ExtraValidRuleInfo{},
-		valid.SceneUpdate: map[valid.Tag]valid.ExtraValidRuleInfo{},
-		valid.SceneQuery:  map[valid.Tag]valid.ExtraValidRuleInfo{},
-		valid.SceneReturn: map[valid.Tag]valid.ExtraValidRuleInfo{},
-		valid.SceneCustom: map[valid.Tag]valid.ExtraValidRuleInfo{},
+		valid.SceneBind: map[valid.Tag]valid.ExtraValidRuleInfo{},
+		valid.SceneSave: map[valid.Tag]valid.ExtraValidRuleInfo{},
+		valid.SceneAdd:  map[valid.Tag]valid.ExtraValidRuleInfo{},
+		valid.SceneUpd:  map[valid.Tag]valid.ExtraValidRuleInfo{},
+		valid.SceneGet:  map[valid.Tag]valid.ExtraValidRuleInfo{},
+		valid.SceneRes:  map[valid.Tag]valid.ExtraValidRuleInfo{},
 	}
 }
 
@@ -89,8 +112,10 @@ func (b *Base) ValidExtraRules() (field.KMap, valid.ExtraValidRules) {
 func (b *Base) ValidStructRules(scene valid.Scene, fn valid.FuncReportError) {
 	switch scene {
 	case valid.SceneAll:
+		// 额外信息大小限制
+		valid.CheckKMapLimits(b.Extra, "Extra", field.DefaultKMapLimits, fn)
 	case valid.SceneBind:
-	case valid.SceneSave:
+	case valid.SceneAdd, valid.SceneUpd:
 		// TODO:GG 这里检查是不是多余了?
 		if b.CreateAt.After(b.UpdateAt) {
 			if b.UpdateAt.Unix() == 0 {
@@ -99,16 +124,22 @@ func (b *Base) ValidStructRules(scene valid.Scene, fn valid.FuncReportError) {
 				fn(b.CreateAt, "CreateAt", valid.TagCheck, "")
 			}
 		}
-	case valid.SceneInsert:
-	case valid.SceneUpdate:
-	case valid.SceneQuery:
-	case valid.SceneReturn:
-	case valid.SceneCustom:
+	case valid.SceneDel:
+	case valid.SceneGet:
+	case valid.SceneRes:
 	default:
 		return
 	}
 }
 
+// ValidFieldDeps 字段依赖(部分验证时，创建/更新时间互相检查)
+func (b *Base) ValidFieldDeps() valid.FieldDeps {
+	return valid.FieldDeps{
+		"CreateAt": {"UpdateAt"},
+		"UpdateAt": {"CreateAt"},
+	}
+}
+
 // ValidLocalizeRules 本地化验证规则
 func (b *Base) ValidLocalizeRules() valid.LocalizeValidRules {
 	return valid.LocalizeValidRules{
@@ -118,13 +149,16 @@ func (b *Base) ValidLocalizeRules() valid.LocalizeValidRules {
 				valid.TagFormat:   {},
 				valid.TagRange:    {},
 				valid.TagCheck: {
-					"CreateAt": {"check_create_at_err", false, nil},
-					"UpdateAt": {"check_update_at_err", false, nil},
-					"DeleteAt": {"check_delete_at_err", false, nil},
+					"CreateAt": {Msg: "check_create_at_err"},
+					"UpdateAt": {Msg: "check_update_at_err"},
+					"DeleteAt": {Msg: "check_delete_at_err"},
+				},
+				valid.TagLimit: {
+					"Extra": {Msg: "limit_extra_err", WithParam: true},
 				},
 			},
 			Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{
-				extKeyAdminNote: {"format_admin_note_err", false, nil},
+				extKeyAdminNote: {Msg: "format_admin_note_err"},
 			},
 		},
 		valid.SceneBind: valid.LocalizeValidRule{
@@ -145,16 +179,7 @@ func (b *Base) ValidLocalizeRules() valid.LocalizeValidRules {
 			},
 			Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{},
 		},
-		valid.SceneInsert: valid.LocalizeValidRule{
-			Rule1: map[valid.Tag]map[valid.FieldName]valid.LocalizeValidRuleParam{
-				valid.TagRequired: {},
-				valid.TagFormat:   {},
-				valid.TagRange:    {},
-				valid.TagCheck:    {},
-			},
-			Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{},
-		},
-		valid.SceneUpdate: valid.LocalizeValidRule{
+		valid.SceneAdd: valid.LocalizeValidRule{
 			Rule1: map[valid.Tag]map[valid.FieldName]valid.LocalizeValidRuleParam{
 				valid.TagRequired: {},
 				valid.TagFormat:   {},
@@ -163,7 +188,7 @@ func (b *Base) ValidLocalizeRules() valid.LocalizeValidRules {
 			},
 			Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{},
 		},
-		valid.SceneQuery: valid.LocalizeValidRule{
+		valid.SceneUpd: valid.LocalizeValidRule{
 			Rule1: map[valid.Tag]map[valid.FieldName]valid.LocalizeValidRuleParam{
 				valid.TagRequired: {},
 				valid.TagFormat:   {},
@@ -172,7 +197,7 @@ func (b *Base) ValidLocalizeRules() valid.LocalizeValidRules {
 			},
 			Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{},
 		},
-		valid.SceneReturn: valid.LocalizeValidRule{
+		valid.SceneGet: valid.LocalizeValidRule{
 			Rule1: map[valid.Tag]map[valid.FieldName]valid.LocalizeValidRuleParam{
 				valid.TagRequired: {},
 				valid.TagFormat:   {},
@@ -181,7 +206,7 @@ func (b *Base) ValidLocalizeRules() valid.LocalizeValidRules {
 			},
 			Rule2: map[valid.Tag]valid.LocalizeValidRuleParam{},
 		},
-		valid.SceneCustom: valid.LocalizeValidRule{
+		valid.SceneRes: valid.LocalizeValidRule{
 			Rule1: map[valid.Tag]map[valid.FieldName]valid.LocalizeValidRuleParam{
 				valid.TagRequired: {},
 				valid.TagFormat:   {},
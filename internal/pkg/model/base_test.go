@@ -0,0 +1,24 @@
+package model
+
+import (
+	"katydid-mp-account/pkg/valid"
+	"testing"
+)
+
+// probeAccount 组合 Base，没有覆盖任何规则方法(无需 RegisterOwnMethods)
+type probeAccount struct {
+	Base
+	Name string `json:"name"`
+}
+
+func TestBaseEmbeddedWithoutOverride(t *testing.T) {
+	acc := &probeAccount{Base: NewBase(0), Name: "probe"}
+	for _, scene := range []valid.Scene{valid.SceneBind, valid.SceneAdd, valid.SceneUpd} {
+		errs, _ := valid.Check(acc, scene)
+		for _, e := range errs {
+			if e.Err != nil {
+				t.Fatalf("%s: Check error %v", scene, e.Err)
+			}
+		}
+	}
+}
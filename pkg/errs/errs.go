@@ -0,0 +1,263 @@
+// Package errs 应用错误(错误码 + HTTP/gRPC状态 + 本地化消息键 + 原因链)
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"katydid-mp-account/pkg/valid"
+	"net/http"
+)
+
+// Code 错误码(响应体中的 code)
+type Code string
+
+const (
+	CodeUnknown            Code = "unknown"               // 未知错误
+	CodeInvalid            Code = "invalid_argument"      // 参数错误
+	CodeValidation         Code = valid.ErrCodeValidation // 验证失败(字段错误见 Errors)
+	CodeUnauthenticated    Code = "unauthenticated"       // 未登录
+	CodePermissionDenied   Code = "permission_denied"     // 无权限
+	CodeNotFound           Code = "not_found"             // 不存在
+	CodeAlreadyExists      Code = "already_exists"        // 已存在
+	CodeConflict           Code = "conflict"              // 冲突(版本不一致/操作与当前结构冲突等)
+	CodeFailedPrecondition Code = "failed_precondition"   // 状态不满足(如组织已停用)
+	CodeTimeout            Code = "deadline_exceeded"     // 超时
+	CodeCanceled           Code = "canceled"              // 请求取消
+	CodeUnavailable        Code = "unavailable"           // 服务不可用(依赖故障)
+	CodeInternal           Code = "internal"              // 内部错误
+)
+
+// gRPC 状态码(与 google.golang.org/grpc/codes 数值一致，避免引入依赖)
+const (
+	GRPCCanceled           uint32 = 1
+	GRPCUnknown            uint32 = 2
+	GRPCInvalidArgument    uint32 = 3
+	GRPCDeadlineExceeded   uint32 = 4
+	GRPCNotFound           uint32 = 5
+	GRPCAlreadyExists      uint32 = 6
+	GRPCPermissionDenied   uint32 = 7
+	GRPCFailedPrecondition uint32 = 9
+	GRPCAborted            uint32 = 10
+	GRPCInternal           uint32 = 13
+	GRPCUnavailable        uint32 = 14
+	GRPCUnauthenticated    uint32 = 16
+)
+
+// codeInfo 错误码 -> HTTP状态/gRPC状态
+var codeInfo = map[Code]struct {
+	http int
+	grpc uint32
+}{
+	CodeUnknown:            {http.StatusInternalServerError, GRPCUnknown},
+	CodeInvalid:            {http.StatusBadRequest, GRPCInvalidArgument},
+	CodeValidation:         {http.StatusBadRequest, GRPCInvalidArgument},
+	CodeUnauthenticated:    {http.StatusUnauthorized, GRPCUnauthenticated},
+	CodePermissionDenied:   {http.StatusForbidden, GRPCPermissionDenied},
+	CodeNotFound:           {http.StatusNotFound, GRPCNotFound},
+	CodeAlreadyExists:      {http.StatusConflict, GRPCAlreadyExists},
+	CodeConflict:           {http.StatusConflict, GRPCAborted},
+	CodeFailedPrecondition: {http.StatusBadRequest, GRPCFailedPrecondition},
+	CodeTimeout:            {http.StatusGatewayTimeout, GRPCDeadlineExceeded},
+	CodeCanceled:           {499, GRPCCanceled}, // 客户端关闭请求(nginx约定)
+	CodeUnavailable:        {http.StatusServiceUnavailable, GRPCUnavailable},
+	CodeInternal:           {http.StatusInternalServerError, GRPCInternal},
+}
+
+// HTTPStatus HTTP状态码
+func (c Code) HTTPStatus() int {
+	if info, ok := codeInfo[c]; ok {
+		return info.http
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode gRPC状态码
+func (c Code) GRPCCode() uint32 {
+	if info, ok := codeInfo[c]; ok {
+		return info.grpc
+	}
+	return GRPCUnknown
+}
+
+// Msg 错误码的默认消息键 (err_<code>)
+func (c Code) Msg() string {
+	if c == CodeValidation {
+		return valid.MsgValidationFailed
+	}
+	return "err_" + string(c)
+}
+
+// msgKeys 业务错误的消息键(RegisterMsg 注册)
+var msgKeys []string
+
+// RegisterMsg 注册业务错误的消息键(在 init 中调用，用于消息目录完整性检查)
+func RegisterMsg(keys ...string) {
+	msgKeys = append(msgKeys, keys...)
+}
+
+// MsgKeys 全部错误码的默认消息键和已注册的业务错误消息键(用于消息目录完整性检查)
+func MsgKeys() []string {
+	keys := make([]string, 0, len(codeInfo)+len(msgKeys))
+	for code := range codeInfo {
+		keys = append(keys, code.Msg())
+	}
+	return append(keys, msgKeys...)
+}
+
+// Error 应用错误，消息键+参数与 valid.MsgErr 一致(i18n.Key 类型的参数会被翻译)
+type Error struct {
+	Code     Code
+	Msg      string          // 消息键(默认为错误码的消息键)
+	Params   []any           // 消息参数
+	Cause    error           // 原因(不输出给客户端)
+	Errors   []*valid.MsgErr // 字段错误
+	Warnings []*valid.MsgErr // 警告/提示
+}
+
+// New 创建错误，msg 为空时使用错误码的默认消息键
+func New(code Code, msg string, params ...any) *Error {
+	if msg == "" {
+		msg = code.Msg()
+	}
+	return &Error{Code: code, Msg: msg, Params: params}
+}
+
+// Wrap 包装原因，cause 为 nil 时返回 nil(无类型的 nil，调用方可直接 err != nil 判断)
+func Wrap(cause error, code Code, msg string, params ...any) error {
+	if cause == nil {
+		return nil
+	}
+	return wrap(cause, code, msg, params...)
+}
+
+// wrap 包装原因(cause 不为 nil)
+func wrap(cause error, code Code, msg string, params ...any) *Error {
+	e := New(code, msg, params...)
+	e.Cause = cause
+	return e
+}
+
+// NotFound 不存在
+func NotFound(msg string, params ...any) *Error { return New(CodeNotFound, msg, params...) }
+
+// AlreadyExists 已存在
+func AlreadyExists(msg string, params ...any) *Error { return New(CodeAlreadyExists, msg, params...) }
+
+// Conflict 冲突
+func Conflict(msg string, params ...any) *Error { return New(CodeConflict, msg, params...) }
+
+// FailedPrecondition 状态不满足
+func FailedPrecondition(msg string, params ...any) *Error {
+	return New(CodeFailedPrecondition, msg, params...)
+}
+
+// Internal 内部错误(原因只记录，不输出)，cause 为 nil 时返回 nil
+func Internal(cause error) error { return Wrap(cause, CodeInternal, "") }
+
+// FromMsgErrs 验证结果 -> 错误(没有阻止操作的错误时返回 nil)
+func FromMsgErrs(msgErrs, warns []*valid.MsgErr) *Error {
+	if len(msgErrs) == 0 {
+		return nil
+	}
+	e := New(CodeValidation, "")
+	e.Errors, e.Warnings = msgErrs, warns
+	for _, msgErr := range msgErrs {
+		if msgErr.Err != nil && msgErr.Field == "" {
+			e.Cause = errors.Join(e.Cause, msgErr.Err) // 验证器本身的错误(规则注册失败/依赖缺失等)
+		}
+	}
+	return e
+}
+
+// FromDB 数据库错误 -> 应用错误(已注册的约束冲突映射为字段验证错误)，err 为 nil 时返回 nil
+func FromDB(obj any, scene valid.Scene, err error) error {
+	if err == nil {
+		return nil
+	}
+	if msgErrs, ok := valid.CheckConstraint(obj, scene, err); ok {
+		if e := FromMsgErrs(msgErrs, nil); e != nil {
+			e.Cause = err
+			return e
+		}
+		return Internal(err)
+	}
+	return From(err)
+}
+
+// Error 错误信息(含原因，用于日志)
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Code, e.Msg)
+	if len(e.Params) > 0 {
+		msg += fmt.Sprintf(" %v", e.Params)
+	}
+	if len(e.Errors) > 0 {
+		msg += fmt.Sprintf(" (%d field errors)", len(e.Errors))
+	}
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+// Unwrap 原因
+func (e *Error) Unwrap() error { return e.Cause }
+
+// Is 错误码相同且 target 为默认消息键(errors.Is(err, errs.New(errs.CodeNotFound, "")))
+// 或消息键相同(业务错误，如 errors.Is(err, hierarchy.ErrCycle))时视为同一错误
+func (e *Error) Is(target error) bool {
+	var t *Error
+	return errors.As(target, &t) && t.Code == e.Code && (t.Msg == t.Code.Msg() || t.Msg == e.Msg)
+}
+
+// HTTPStatus HTTP状态码
+func (e *Error) HTTPStatus() int { return e.Code.HTTPStatus() }
+
+// GRPCCode gRPC状态码
+func (e *Error) GRPCCode() uint32 { return e.Code.GRPCCode() }
+
+// Envelope 错误 -> 标准错误响应体(与验证错误响应体一致)
+func (e *Error) Envelope() *valid.ErrEnvelope {
+	return &valid.ErrEnvelope{
+		Code:     string(e.Code),
+		Msg:      e.Msg,
+		Params:   e.Params,
+		Errors:   e.Errors,
+		Warnings: e.Warnings,
+	}
+}
+
+// From 任意错误 -> 应用错误(原因链中的 *Error 优先，其次按已知错误推断错误码)
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return wrap(err, CodeTimeout, "")
+	case errors.Is(err, context.Canceled):
+		return wrap(err, CodeCanceled, "")
+	}
+	return wrap(err, CodeInternal, "")
+}
+
+// CodeOf 错误码(nil 返回空)
+func CodeOf(err error) Code {
+	if e := From(err); e != nil {
+		return e.Code
+	}
+	return ""
+}
+
+// ToEnvelope 任意错误 -> 标准错误响应体和HTTP状态码
+func ToEnvelope(err error) (*valid.ErrEnvelope, int) {
+	e := From(err)
+	if e == nil {
+		return nil, http.StatusOK
+	}
+	return e.Envelope(), e.HTTPStatus()
+}
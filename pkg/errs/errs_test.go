@@ -0,0 +1,76 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"katydid-mp-account/pkg/valid"
+	"net/http"
+	"testing"
+)
+
+func TestNilCause(t *testing.T) {
+	// 调用方通过 error 接口返回，nil 原因不能变成有类型的 nil
+	tests := map[string]func() error{
+		"Wrap":     func() error { return Wrap(nil, CodeNotFound, "") },
+		"Internal": func() error { return Internal(nil) },
+		"FromDB":   func() error { return FromDB(nil, valid.SceneAdd, nil) },
+	}
+	for name, fn := range tests {
+		if err := fn(); err != nil {
+			t.Errorf("%s(nil) = %#v, want nil", name, err)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("db down")
+	err := Wrap(cause, CodeUnavailable, "")
+	var e *Error
+	if !errors.As(err, &e) || e.Code != CodeUnavailable || e.Msg != CodeUnavailable.Msg() {
+		t.Fatalf("Wrap = %#v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("Wrap should keep cause in chain")
+	}
+	if got := e.HTTPStatus(); got != http.StatusServiceUnavailable {
+		t.Fatalf("HTTPStatus = %d", got)
+	}
+}
+
+func TestIs(t *testing.T) {
+	errBiz := New(CodeFailedPrecondition, "err_biz")
+	err := fmt.Errorf("op: %w", New(CodeFailedPrecondition, "err_biz", 1))
+	tests := []struct {
+		target error
+		want   bool
+	}{
+		{New(CodeFailedPrecondition, ""), true}, // 默认消息键: 只比较错误码
+		{errBiz, true},                          // 业务错误: 比较消息键
+		{New(CodeFailedPrecondition, "err_other"), false},
+		{New(CodeNotFound, ""), false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%v, %v) = %v, want %v", err, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Code
+	}{
+		{nil, ""},
+		{context.DeadlineExceeded, CodeTimeout},
+		{fmt.Errorf("query: %w", context.Canceled), CodeCanceled},
+		{errors.New("boom"), CodeInternal},
+		{fmt.Errorf("svc: %w", NotFound("")), CodeNotFound},
+	}
+	for _, tt := range tests {
+		if got := CodeOf(tt.err); got != tt.want {
+			t.Errorf("CodeOf(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package field
 
 import (
 	"math"
+	"reflect"
 )
 
 // KMap 扩展 map[string]any 类型
@@ -1102,3 +1103,113 @@ func (m KMap) Keys() []string {
 	}
 	return keys
 }
+
+// Clone 深拷贝，返回的map与原map不共享任何可变数据
+func (m KMap) Clone() KMap {
+	if m == nil {
+		return nil
+	}
+	clone := make(KMap, len(m))
+	for k, v := range m {
+		clone[k] = cloneValue(v)
+	}
+	return clone
+}
+
+// cloneValue 深拷贝任意值(常用类型直接处理，其余类型反射递归拷贝)
+func cloneValue(value any) any {
+	switch val := value.(type) {
+	case nil, bool, string,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64,
+		Secret: // 敏感值创建后不再修改
+		return val
+	case KMap:
+		return val.Clone()
+	case map[string]any:
+		if val == nil {
+			return val
+		}
+		clone := make(map[string]any, len(val))
+		for k, v := range val {
+			clone[k] = cloneValue(v)
+		}
+		return clone
+	case []any:
+		if val == nil {
+			return val
+		}
+		clone := make([]any, len(val))
+		for i, v := range val {
+			clone[i] = cloneValue(v)
+		}
+		return clone
+	case []KMap:
+		if val == nil {
+			return val
+		}
+		clone := make([]KMap, len(val))
+		for i, v := range val {
+			clone[i] = v.Clone()
+		}
+		return clone
+	}
+	return cloneReflect(reflect.ValueOf(value)).Interface()
+}
+
+// cloneReflect 反射深拷贝(指针/接口/结构体/切片/数组/map 递归)
+// 结构体的未导出字段无法通过反射设置，只能浅拷贝；不支持循环引用
+func cloneReflect(rv reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		clone := reflect.New(rv.Type()).Elem()
+		clone.Set(cloneReflect(rv.Elem()))
+		return clone
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return rv
+		}
+		clone := reflect.New(rv.Type().Elem())
+		clone.Elem().Set(cloneReflect(rv.Elem()))
+		return clone
+	case reflect.Struct:
+		clone := reflect.New(rv.Type()).Elem()
+		clone.Set(rv)
+		for i := 0; i < rv.NumField(); i++ {
+			if f := clone.Field(i); f.CanSet() {
+				f.Set(cloneReflect(rv.Field(i)))
+			}
+		}
+		return clone
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		clone := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			clone.Index(i).Set(cloneReflect(rv.Index(i)))
+		}
+		return clone
+	case reflect.Array:
+		clone := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			clone.Index(i).Set(cloneReflect(rv.Index(i)))
+		}
+		return clone
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		clone := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), cloneReflect(iter.Value()))
+		}
+		return clone
+	}
+	return rv
+}
@@ -0,0 +1,551 @@
+package field
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// 二进制编码(缓存用)，保留Go类型(int/float/[]byte/嵌套KMap等)
+// 格式: | 1位版本 | 值(KMap) |
+// 值: | 1位类型 | 数据 |，变长整数使用 varint/uvarint，长度前缀使用 uvarint
+const kMapBinaryVersion byte = 1
+
+// 值类型
+const (
+	binNil byte = iota
+	binFalse
+	binTrue
+	binInt
+	binInt8
+	binInt16
+	binInt32
+	binInt64
+	binUint
+	binUint8
+	binUint16
+	binUint32
+	binUint64
+	binFloat32
+	binFloat64
+	binString
+	binBytes
+	binKMap
+	binMap
+	binSlice
+	binKMapSlice
+	binSecret
+	binIntSlice
+	binInt8Slice
+	binInt16Slice
+	binInt32Slice
+	binInt64Slice
+	binUintSlice
+	binUint16Slice
+	binUint32Slice
+	binUint64Slice
+	binFloat32Slice
+	binFloat64Slice
+	binBoolSlice
+	binStringSlice
+)
+
+var errBinaryShort = errors.New("kmap binary data too short")
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+func (m KMap) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(make([]byte, 0, 64))
+}
+
+// AppendBinary 实现 encoding.BinaryAppender
+func (m KMap) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, kMapBinaryVersion)
+	if m == nil {
+		return append(b, binNil), nil
+	}
+	return appendBinaryValue(b, withSecretKeys(m))
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，并按 DefaultKMapLimits 检查
+func (m *KMap) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errBinaryShort
+	}
+	limits := DefaultKMapLimits
+	if limits.MaxBytes > 0 && len(data) > limits.MaxBytes {
+		return &KMapLimitError{Limit: KMapLimitBytes, Max: limits.MaxBytes, Actual: len(data)}
+	}
+	if data[0] != kMapBinaryVersion {
+		return fmt.Errorf("kmap binary version %d unsupported", data[0])
+	}
+	d := &binaryDecoder{data: data[1:]}
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+	if len(d.data) != 0 {
+		return fmt.Errorf("kmap binary has %d trailing bytes", len(d.data))
+	}
+	switch val := value.(type) {
+	case nil:
+		*m = nil
+	case KMap:
+		keys := 0
+		if err = checkLimits(val, "", 0, &keys, limits); err != nil {
+			return err
+		}
+		openSecretKeys(val)
+		*m = val
+	default:
+		return fmt.Errorf("kmap binary root type %T invalid", value)
+	}
+	return nil
+}
+
+func appendBinaryValue(b []byte, value any) ([]byte, error) {
+	switch val := value.(type) {
+	case nil:
+		return append(b, binNil), nil
+	case bool:
+		if val {
+			return append(b, binTrue), nil
+		}
+		return append(b, binFalse), nil
+	case int:
+		return binary.AppendVarint(append(b, binInt), int64(val)), nil
+	case int8:
+		return append(b, binInt8, byte(val)), nil
+	case int16:
+		return binary.AppendVarint(append(b, binInt16), int64(val)), nil
+	case int32:
+		return binary.AppendVarint(append(b, binInt32), int64(val)), nil
+	case int64:
+		return binary.AppendVarint(append(b, binInt64), val), nil
+	case uint:
+		return binary.AppendUvarint(append(b, binUint), uint64(val)), nil
+	case uint8:
+		return append(b, binUint8, val), nil
+	case uint16:
+		return binary.AppendUvarint(append(b, binUint16), uint64(val)), nil
+	case uint32:
+		return binary.AppendUvarint(append(b, binUint32), uint64(val)), nil
+	case uint64:
+		return binary.AppendUvarint(append(b, binUint64), val), nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(append(b, binFloat32), math.Float32bits(val)), nil
+	case float64:
+		return binary.LittleEndian.AppendUint64(append(b, binFloat64), math.Float64bits(val)), nil
+	case string:
+		return appendBinaryString(append(b, binString), val), nil
+	case []byte:
+		return appendBinaryBytes(append(b, binBytes), val), nil
+	case Secret:
+		sealed, err := val.Seal()
+		if err != nil {
+			return nil, err
+		}
+		return appendBinaryBytes(append(b, binSecret), sealed), nil
+	case KMap:
+		return appendBinaryMap(append(b, binKMap), val)
+	case map[string]any:
+		return appendBinaryMap(append(b, binMap), val)
+	case []any:
+		b = binary.AppendUvarint(append(b, binSlice), uint64(len(val)))
+		for _, item := range val {
+			var err error
+			if b, err = appendBinaryValue(b, item); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case []KMap:
+		b = binary.AppendUvarint(append(b, binKMapSlice), uint64(len(val)))
+		for _, item := range val {
+			var err error
+			if b, err = appendBinaryMap(b, item); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case []int:
+		return appendBinaryVarints(append(b, binIntSlice), val), nil
+	case []int8:
+		b = binary.AppendUvarint(append(b, binInt8Slice), uint64(len(val)))
+		for _, item := range val {
+			b = append(b, byte(item))
+		}
+		return b, nil
+	case []int16:
+		return appendBinaryVarints(append(b, binInt16Slice), val), nil
+	case []int32:
+		return appendBinaryVarints(append(b, binInt32Slice), val), nil
+	case []int64:
+		return appendBinaryVarints(append(b, binInt64Slice), val), nil
+	case []uint:
+		return appendBinaryUvarints(append(b, binUintSlice), val), nil
+	case []uint16:
+		return appendBinaryUvarints(append(b, binUint16Slice), val), nil
+	case []uint32:
+		return appendBinaryUvarints(append(b, binUint32Slice), val), nil
+	case []uint64:
+		return appendBinaryUvarints(append(b, binUint64Slice), val), nil
+	case []float32:
+		b = binary.AppendUvarint(append(b, binFloat32Slice), uint64(len(val)))
+		for _, item := range val {
+			b = binary.LittleEndian.AppendUint32(b, math.Float32bits(item))
+		}
+		return b, nil
+	case []float64:
+		b = binary.AppendUvarint(append(b, binFloat64Slice), uint64(len(val)))
+		for _, item := range val {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(item))
+		}
+		return b, nil
+	case []bool:
+		b = binary.AppendUvarint(append(b, binBoolSlice), uint64(len(val)))
+		for _, item := range val {
+			if item {
+				b = append(b, 1)
+			} else {
+				b = append(b, 0)
+			}
+		}
+		return b, nil
+	case []string:
+		b = binary.AppendUvarint(append(b, binStringSlice), uint64(len(val)))
+		for _, item := range val {
+			b = appendBinaryString(b, item)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("kmap binary unsupported type %T", value)
+}
+
+func appendBinaryMap(b []byte, m map[string]any) ([]byte, error) {
+	b = binary.AppendUvarint(b, uint64(len(m)))
+	for k, v := range m {
+		b = appendBinaryString(b, k)
+		var err error
+		if b, err = appendBinaryValue(b, v); err != nil {
+			return nil, fmt.Errorf("kmap binary key %q: %w", k, err)
+		}
+	}
+	return b, nil
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendBinaryBytes(b []byte, bytes []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(bytes)))
+	return append(b, bytes...)
+}
+
+func appendBinaryVarints[T int | int16 | int32 | int64](b []byte, values []T) []byte {
+	b = binary.AppendUvarint(b, uint64(len(values)))
+	for _, item := range values {
+		b = binary.AppendVarint(b, int64(item))
+	}
+	return b
+}
+
+func appendBinaryUvarints[T uint | uint16 | uint32 | uint64](b []byte, values []T) []byte {
+	b = binary.AppendUvarint(b, uint64(len(values)))
+	for _, item := range values {
+		b = binary.AppendUvarint(b, uint64(item))
+	}
+	return b
+}
+
+// binaryDecoder 二进制解码器
+type binaryDecoder struct {
+	data []byte
+}
+
+func (d *binaryDecoder) byte() (byte, error) {
+	if len(d.data) < 1 {
+		return 0, errBinaryShort
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b, nil
+}
+
+func (d *binaryDecoder) varint() (int64, error) {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		return 0, errBinaryShort
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+func (d *binaryDecoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errBinaryShort
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+// length 读取长度前缀，size 为每个元素最少占用的字节数(防止恶意长度)
+func (d *binaryDecoder) length(size int) (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)/size) {
+		return 0, errBinaryShort
+	}
+	return int(n), nil
+}
+
+func (d *binaryDecoder) fixed(n int) ([]byte, error) {
+	if len(d.data) < n {
+		return nil, errBinaryShort
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+func (d *binaryDecoder) bytes() ([]byte, error) {
+	n, err := d.length(1)
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.fixed(n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), b...), nil
+}
+
+func (d *binaryDecoder) string() (string, error) {
+	n, err := d.length(1)
+	if err != nil {
+		return "", err
+	}
+	b, err := d.fixed(n)
+	return string(b), err
+}
+
+func (d *binaryDecoder) kMap() (map[string]any, error) {
+	n, err := d.length(2)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if m[k], err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (d *binaryDecoder) value() (any, error) {
+	typ, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case binNil:
+		return nil, nil
+	case binFalse:
+		return false, nil
+	case binTrue:
+		return true, nil
+	case binInt:
+		v, err := d.varint()
+		return int(v), err
+	case binInt8:
+		v, err := d.byte()
+		return int8(v), err
+	case binInt16:
+		v, err := d.varint()
+		return int16(v), err
+	case binInt32:
+		v, err := d.varint()
+		return int32(v), err
+	case binInt64:
+		return d.varint()
+	case binUint:
+		v, err := d.uvarint()
+		return uint(v), err
+	case binUint8:
+		return d.byte()
+	case binUint16:
+		v, err := d.uvarint()
+		return uint16(v), err
+	case binUint32:
+		v, err := d.uvarint()
+		return uint32(v), err
+	case binUint64:
+		return d.uvarint()
+	case binFloat32:
+		b, err := d.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case binFloat64:
+		b, err := d.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case binString:
+		return d.string()
+	case binBytes:
+		return d.bytes()
+	case binSecret:
+		sealed, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return Secret{sealed: sealed}, nil
+	case binKMap:
+		m, err := d.kMap()
+		return KMap(m), err
+	case binMap:
+		return d.kMap()
+	case binSlice:
+		n, err := d.length(1)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, n)
+		for i := range values {
+			if values[i], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	case binKMapSlice:
+		n, err := d.length(1)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]KMap, n)
+		for i := range values {
+			m, err := d.kMap()
+			if err != nil {
+				return nil, err
+			}
+			values[i] = m
+		}
+		return values, nil
+	case binIntSlice:
+		return decodeBinaryVarints[int](d)
+	case binInt8Slice:
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]int8, len(b))
+		for i, item := range b {
+			values[i] = int8(item)
+		}
+		return values, nil
+	case binInt16Slice:
+		return decodeBinaryVarints[int16](d)
+	case binInt32Slice:
+		return decodeBinaryVarints[int32](d)
+	case binInt64Slice:
+		return decodeBinaryVarints[int64](d)
+	case binUintSlice:
+		return decodeBinaryUvarints[uint](d)
+	case binUint16Slice:
+		return decodeBinaryUvarints[uint16](d)
+	case binUint32Slice:
+		return decodeBinaryUvarints[uint32](d)
+	case binUint64Slice:
+		return decodeBinaryUvarints[uint64](d)
+	case binFloat32Slice:
+		n, err := d.length(4)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]float32, n)
+		for i := range values {
+			b, _ := d.fixed(4)
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		}
+		return values, nil
+	case binFloat64Slice:
+		n, err := d.length(8)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]float64, n)
+		for i := range values {
+			b, _ := d.fixed(8)
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return values, nil
+	case binBoolSlice:
+		b, err := d.bytes()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]bool, len(b))
+		for i, item := range b {
+			values[i] = item != 0
+		}
+		return values, nil
+	case binStringSlice:
+		n, err := d.length(1)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, n)
+		for i := range values {
+			if values[i], err = d.string(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("kmap binary type %d unsupported", typ)
+}
+
+func decodeBinaryVarints[T int | int16 | int32 | int64](d *binaryDecoder) ([]T, error) {
+	n, err := d.length(1)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, n)
+	for i := range values {
+		v, err := d.varint()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = T(v)
+	}
+	return values, nil
+}
+
+func decodeBinaryUvarints[T uint | uint16 | uint32 | uint64](d *binaryDecoder) ([]T, error) {
+	n, err := d.length(1)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, n)
+	for i := range values {
+		v, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = T(v)
+	}
+	return values, nil
+}
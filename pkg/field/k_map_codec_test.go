@@ -0,0 +1,169 @@
+package field
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestKMapBinaryRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		m    KMap
+	}{
+		{"nil", nil},
+		{"empty", KMap{}},
+		{"scalars", KMap{
+			"nil": nil, "bool": true, "int": -1, "int8": int8(-8), "int64": int64(1 << 40),
+			"uint8": uint8(8), "uint64": uint64(1 << 63), "float32": float32(1.5), "float64": 2.25,
+			"string": "s", "bytes": []byte{0, 1, 2},
+		}},
+		{"slices", KMap{
+			"ints": []int{1, -2}, "int64s": []int64{3}, "strings": []string{"a", ""},
+			"bools": []bool{true, false}, "floats": []float64{0.5}, "any": []any{1, "a", nil},
+		}},
+		{"nested", KMap{
+			"kmap":  KMap{"inner": KMap{"deep": []any{KMap{"k": "v"}}}},
+			"map":   map[string]any{"n": 1},
+			"kmaps": []KMap{{"a": 1}, {"b": "2"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got KMap
+			if err = got.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.m) {
+				t.Fatalf("round-trip mismatch:\n got %#v\nwant %#v", got, tt.m)
+			}
+		})
+	}
+}
+
+func TestKMapBinarySecret(t *testing.T) {
+	withTestCipher(t)
+
+	src := KMap{"token": NewSecret("tk"), testSecretKey: "pwd"}
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 读出为密文，无需加密器即可再次编码(缓存回写)
+	SetSecretCipher(nil)
+	var sealed KMap
+	if err = sealed.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sealed.GetSecret("token"); ok {
+		t.Fatal("sealed secret revealed without cipher")
+	}
+	if _, err = sealed.MarshalBinary(); err != nil {
+		t.Fatalf("re-encode sealed secret without cipher: %v", err)
+	}
+
+	withTestCipher(t)
+	for key, want := range map[string]string{"token": "tk", testSecretKey: "pwd"} {
+		if got, _ := sealed.GetSecret(key); got != want {
+			t.Fatalf("GetSecret(%s) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestKMapBinarySecretWithoutCipher(t *testing.T) {
+	SetSecretCipher(nil)
+	if _, err := (KMap{"token": NewSecret("tk")}).MarshalBinary(); err == nil {
+		t.Fatal("marshal plain secret without cipher should fail")
+	}
+}
+
+func TestKMapBinaryInvalid(t *testing.T) {
+	valid, err := KMap{"k": "v"}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownVersion := append([]byte{kMapBinaryVersion + 1}, valid[1:]...)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unknown version", unknownVersion},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing", append(append([]byte{}, valid...), 0)},
+		{"root not kmap", []byte{kMapBinaryVersion, binString, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m KMap
+			if err := m.UnmarshalBinary(tt.data); err == nil {
+				t.Fatalf("UnmarshalBinary(%v) = nil error, got %#v", tt.data, m)
+			}
+		})
+	}
+}
+
+var benchKMap = KMap{
+	"websiteUrl": "https://katydid.example.com",
+	"faviconUrl": "https://katydid.example.com/favicon.ico",
+	"desc":       "katydid organization for benchmark",
+	"multiJob":   true,
+	"level":      3,
+	"score":      98.5,
+	"addresses":  []string{"address line 1", "address line 2", "address line 3"},
+	"contacts":   []string{"contact@katydid.example.com", "+86 000 0000 0000"},
+	"ids":        []int64{1, 2, 3, 4, 5, 6, 7, 8},
+	"avatar":     []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a},
+	"settings": KMap{
+		"theme":  "dark",
+		"notify": true,
+		"limits": map[string]any{"members": 100, "teams": 10},
+	},
+}
+
+func BenchmarkKMapJSONMarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(benchKMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkKMapBinaryMarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := benchKMap.MarshalBinary(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkKMapJSONUnmarshal(b *testing.B) {
+	data, _ := json.Marshal(benchKMap)
+	b.ReportAllocs()
+	b.ReportMetric(float64(len(data)), "B/value")
+	for i := 0; i < b.N; i++ {
+		var m KMap
+		if err := json.Unmarshal(data, &m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkKMapBinaryUnmarshal(b *testing.B) {
+	data, _ := benchKMap.MarshalBinary()
+	b.ReportAllocs()
+	b.ReportMetric(float64(len(data)), "B/value")
+	for i := 0; i < b.N; i++ {
+		var m KMap
+		if err := m.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package field
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// KMapLimits KMap 载荷限制(<=0 表示不限制)
+type KMapLimits struct {
+	MaxKeys   int // 最大键数(含嵌套)
+	MaxDepth  int // 最大嵌套深度(顶层值为1)
+	MaxBytes  int // 最大序列化字节数
+	MaxStrLen int // 最大字符串长度(字节，含键)
+}
+
+// DefaultKMapLimits 默认限制，反序列化(JSON/二进制)时生效(启动时可修改)
+var DefaultKMapLimits = KMapLimits{
+	MaxKeys:   1_000,
+	MaxDepth:  8,
+	MaxBytes:  64 << 10,
+	MaxStrLen: 16 << 10,
+}
+
+// 限制类型
+const (
+	KMapLimitKeys   = "keys"
+	KMapLimitDepth  = "depth"
+	KMapLimitBytes  = "bytes"
+	KMapLimitStrLen = "strLen"
+)
+
+// KMapLimitError 超出限制的错误
+type KMapLimitError struct {
+	Key    string // 超限的键路径(如 a.b[2])，整体超限时为空
+	Limit  string // 超出的限制
+	Max    int
+	Actual int
+}
+
+func (e *KMapLimitError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("kmap %s limit exceeded: %d > %d", e.Limit, e.Actual, e.Max)
+	}
+	return fmt.Sprintf("kmap key %q %s limit exceeded: %d > %d", e.Key, e.Limit, e.Actual, e.Max)
+}
+
+// CheckLimits 检查是否超出限制，超限时返回 *KMapLimitError，值无法序列化时返回其他错误
+// 字节数按明文计算(敏感值不加密，已有密文按密文计算)
+func (m KMap) CheckLimits(limits KMapLimits) error {
+	if limits.MaxBytes > 0 {
+		bytes, err := json.Marshal(plainValue(m))
+		if err != nil {
+			return err
+		}
+		if len(bytes) > limits.MaxBytes {
+			return &KMapLimitError{Limit: KMapLimitBytes, Max: limits.MaxBytes, Actual: len(bytes)}
+		}
+	}
+	keys := 0
+	return checkLimits(map[string]any(m), "", 0, &keys, limits)
+}
+
+// UnmarshalJSON 反序列化，并按 DefaultKMapLimits 检查(密文包装还原为 Secret)
+func (m *KMap) UnmarshalJSON(data []byte) error {
+	limits := DefaultKMapLimits
+	if limits.MaxBytes > 0 && len(data) > limits.MaxBytes {
+		return &KMapLimitError{Limit: KMapLimitBytes, Max: limits.MaxBytes, Actual: len(data)}
+	}
+
+	var kMap map[string]any
+	if err := json.Unmarshal(data, &kMap); err != nil {
+		return err
+	}
+	keys := 0
+	if err := checkLimits(kMap, "", 0, &keys, limits); err != nil {
+		return err
+	}
+	opened, err := openMap(kMap)
+	if err != nil {
+		return err
+	}
+	*m = opened
+	return nil
+}
+
+// plainValue 递归将 KMap 转为普通 map，Secret 转为明文(已有密文时为 base64 密文)，不调用加密器
+func plainValue(value any) any {
+	switch val := value.(type) {
+	case Secret:
+		if val.sealed != nil {
+			return map[string]any{secretSealedKey: base64.StdEncoding.EncodeToString(val.sealed)}
+		}
+		return string(val.plain)
+	case KMap:
+		return plainValue(map[string]any(val))
+	case map[string]any:
+		plain := make(map[string]any, len(val))
+		for k, v := range val {
+			plain[k] = plainValue(v)
+		}
+		return plain
+	case []any:
+		plain := make([]any, len(val))
+		for i, v := range val {
+			plain[i] = plainValue(v)
+		}
+		return plain
+	case []KMap:
+		plain := make([]any, len(val))
+		for i, v := range val {
+			plain[i] = plainValue(v)
+		}
+		return plain
+	}
+	return value
+}
+
+// checkLimits 递归检查键数/深度/字符串长度
+func checkLimits(value any, path string, depth int, keys *int, limits KMapLimits) error {
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return &KMapLimitError{Key: path, Limit: KMapLimitDepth, Max: limits.MaxDepth, Actual: depth}
+	}
+
+	switch val := value.(type) {
+	case KMap:
+		return checkLimits(map[string]any(val), path, depth, keys, limits)
+	case map[string]any:
+		for k, v := range val {
+			keyPath := k
+			if path != "" {
+				keyPath = path + "." + k
+			}
+			*keys++
+			if limits.MaxKeys > 0 && *keys > limits.MaxKeys {
+				return &KMapLimitError{Key: keyPath, Limit: KMapLimitKeys, Max: limits.MaxKeys, Actual: *keys}
+			}
+			if limits.MaxStrLen > 0 && len(k) > limits.MaxStrLen {
+				return &KMapLimitError{Key: keyPath, Limit: KMapLimitStrLen, Max: limits.MaxStrLen, Actual: len(k)}
+			}
+			if err := checkLimits(v, keyPath, depth+1, keys, limits); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, v := range val {
+			if err := checkLimits(v, path+"["+strconv.Itoa(i)+"]", depth+1, keys, limits); err != nil {
+				return err
+			}
+		}
+	case []KMap:
+		for i, v := range val {
+			if err := checkLimits(v, path+"["+strconv.Itoa(i)+"]", depth+1, keys, limits); err != nil {
+				return err
+			}
+		}
+	case []string:
+		for i, v := range val {
+			if limits.MaxStrLen > 0 && len(v) > limits.MaxStrLen {
+				return &KMapLimitError{Key: path + "[" + strconv.Itoa(i) + "]", Limit: KMapLimitStrLen, Max: limits.MaxStrLen, Actual: len(v)}
+			}
+		}
+	case string:
+		if limits.MaxStrLen > 0 && len(val) > limits.MaxStrLen {
+			return &KMapLimitError{Key: path, Limit: KMapLimitStrLen, Max: limits.MaxStrLen, Actual: len(val)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package field
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// countCipher 记录加密次数
+type countCipher struct {
+	SecretCipher
+	encrypts int
+}
+
+func (c *countCipher) Encrypt(plain []byte) ([]byte, error) {
+	c.encrypts++
+	return c.SecretCipher.Encrypt(plain)
+}
+
+func TestCheckLimitsPlainSecret(t *testing.T) {
+	m := KMap{"token": NewSecret("tk"), "name": "n"}
+
+	// 未配置加密器: 不影响限制检查
+	SetSecretCipher(nil)
+	if err := m.CheckLimits(DefaultKMapLimits); err != nil {
+		t.Fatalf("CheckLimits without cipher = %v", err)
+	}
+
+	// 配置加密器: 不加密，按明文计算字节数
+	inner, err := NewEnvelopeCipher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &countCipher{SecretCipher: inner}
+	SetSecretCipher(c)
+	t.Cleanup(func() { SetSecretCipher(nil) })
+
+	plainSize := len(`{"name":"n","token":"tk"}`)
+	if err = m.CheckLimits(KMapLimits{MaxBytes: plainSize}); err != nil {
+		t.Fatalf("CheckLimits(MaxBytes=%d) = %v", plainSize, err)
+	}
+	var limitErr *KMapLimitError
+	if err = m.CheckLimits(KMapLimits{MaxBytes: plainSize - 1}); !errors.As(err, &limitErr) || limitErr.Actual != plainSize {
+		t.Fatalf("CheckLimits(MaxBytes=%d) = %v, want bytes %d", plainSize-1, err, plainSize)
+	}
+	if c.encrypts != 0 {
+		t.Fatalf("CheckLimits encrypted %d times", c.encrypts)
+	}
+}
+
+func TestCheckLimitsUnsupported(t *testing.T) {
+	err := KMap{"ch": make(chan int)}.CheckLimits(DefaultKMapLimits)
+	var limitErr *KMapLimitError
+	if err == nil || errors.As(err, &limitErr) {
+		t.Fatalf("CheckLimits(chan) = %v, want non-limit error", err)
+	}
+}
+
+func TestKMapBinaryLimits(t *testing.T) {
+	nested := KMap{"v": 1}
+	for i := 0; i < DefaultKMapLimits.MaxDepth; i++ {
+		nested = KMap{"n": nested}
+	}
+	keys := KMap{}
+	for i := 0; i <= DefaultKMapLimits.MaxKeys; i++ {
+		keys["k"+strconv.Itoa(i)] = i
+	}
+	tests := []struct {
+		name  string
+		m     KMap
+		limit string
+	}{
+		{"bytes", KMap{"s": strings.Repeat("s", DefaultKMapLimits.MaxBytes)}, KMapLimitBytes},
+		{"depth", nested, KMapLimitDepth},
+		{"keys", keys, KMapLimitKeys},
+		{"strLen", KMap{"s": strings.Repeat("s", DefaultKMapLimits.MaxStrLen+1)}, KMapLimitStrLen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got KMap
+			var limitErr *KMapLimitError
+			if err = got.UnmarshalBinary(data); !errors.As(err, &limitErr) || limitErr.Limit != tt.limit {
+				t.Fatalf("UnmarshalBinary = %v, want %s limit", err, tt.limit)
+			}
+		})
+	}
+}
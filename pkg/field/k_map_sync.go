@@ -0,0 +1,568 @@
+package field
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// SyncKMap 并发安全的 KMap，读写加锁，写入/读出的可变值(slice/map)均深拷贝
+type SyncKMap struct {
+	mu   sync.RWMutex
+	data KMap
+}
+
+// NewSyncKMap 创建并发安全的 KMap，data 会被深拷贝
+func NewSyncKMap(data KMap) *SyncKMap {
+	if data == nil {
+		return &SyncKMap{data: make(KMap)}
+	}
+	return &SyncKMap{data: data.Clone()}
+}
+
+// Snapshot 获取当前数据的深拷贝快照，快照可在协程间随意传递
+func (m *SyncKMap) Snapshot() KMap {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.Clone()
+}
+
+// Clone 深拷贝出新的 SyncKMap
+func (m *SyncKMap) Clone() *SyncKMap {
+	return &SyncKMap{data: m.Snapshot()}
+}
+
+// Update 在写锁内批量修改，fn 内不可调用 m 的其他方法，也不可持有 data 的引用
+func (m *SyncKMap) Update(fn func(data KMap)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(m.data)
+}
+
+// Range 在读锁内遍历(值为深拷贝)，fn 返回 false 时停止
+func (m *SyncKMap) Range(fn func(key string, value any) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for k, v := range m.data {
+		if !fn(k, cloneValue(v)) {
+			return
+		}
+	}
+}
+
+// MarshalJSON 序列化当前快照
+func (m *SyncKMap) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return json.Marshal(m.data)
+}
+
+// UnmarshalJSON 反序列化并整体替换数据
+func (m *SyncKMap) UnmarshalJSON(data []byte) error {
+	var kMap KMap
+	if err := json.Unmarshal(data, &kMap); err != nil {
+		return err
+	}
+	if kMap == nil {
+		kMap = make(KMap)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = kMap
+	return nil
+}
+
+// Set 设置任意类型值
+func (m *SyncKMap) Set(key string, value any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.Set(key, cloneValue(value))
+}
+
+// SetPtr 设置指针型值
+func (m *SyncKMap) SetPtr(key string, value *any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetPtr(key, clonePtr(value))
+}
+
+// Delete 删除指定key
+func (m *SyncKMap) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.Delete(key)
+}
+
+// Get 获取任意类型值，需要自己做类型断言
+func (m *SyncKMap) Get(key string) (any, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.Get(key)
+	return cloneValue(v), ok
+}
+
+// SetSlice 设置[]any类型值
+func (m *SyncKMap) SetSlice(key string, value *[]any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetSlice(key, clonePtr(value))
+}
+
+// GetSlice 获取[]any类型值
+func (m *SyncKMap) GetSlice(key string) ([]any, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetSlice(key)
+	return cloneAs(v), ok
+}
+
+// SetInt 设置int类型值
+func (m *SyncKMap) SetInt(key string, value *int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt(key, value)
+}
+
+// GetInt 获取int类型值
+func (m *SyncKMap) GetInt(key string) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetInt(key)
+}
+
+// SetIntSlice 设置[]int类型值
+func (m *SyncKMap) SetIntSlice(key string, value *[]int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetIntSlice(key, clonePtr(value))
+}
+
+// GetIntSlice 获取[]int类型值
+func (m *SyncKMap) GetIntSlice(key string) ([]int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetIntSlice(key)
+	return cloneAs(v), ok
+}
+
+// SetInt8 设置int8类型值
+func (m *SyncKMap) SetInt8(key string, value *int8) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt8(key, value)
+}
+
+// GetInt8 获取int8类型值
+func (m *SyncKMap) GetInt8(key string) (int8, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetInt8(key)
+}
+
+// SetInt8Slice 设置[]int8类型值
+func (m *SyncKMap) SetInt8Slice(key string, value *[]int8) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt8Slice(key, clonePtr(value))
+}
+
+// GetInt8Slice 获取[]int8类型值
+func (m *SyncKMap) GetInt8Slice(key string) ([]int8, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetInt8Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetInt16 设置int16类型值
+func (m *SyncKMap) SetInt16(key string, value *int16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt16(key, value)
+}
+
+// GetInt16 获取int16类型值
+func (m *SyncKMap) GetInt16(key string) (int16, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetInt16(key)
+}
+
+// SetInt16Slice 设置[]int16类型值
+func (m *SyncKMap) SetInt16Slice(key string, value *[]int16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt16Slice(key, clonePtr(value))
+}
+
+// GetInt16Slice 获取[]int16类型值
+func (m *SyncKMap) GetInt16Slice(key string) ([]int16, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetInt16Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetInt64 设置int64类型值
+func (m *SyncKMap) SetInt64(key string, value *int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt64(key, value)
+}
+
+// GetInt64 获取int64类型值
+func (m *SyncKMap) GetInt64(key string) (int64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetInt64(key)
+}
+
+// SetInt64Slice 设置[]int64类型值
+func (m *SyncKMap) SetInt64Slice(key string, value *[]int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetInt64Slice(key, clonePtr(value))
+}
+
+// GetInt64Slice 获取[]int64类型值
+func (m *SyncKMap) GetInt64Slice(key string) ([]int64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetInt64Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetUint 设置uint类型值
+func (m *SyncKMap) SetUint(key string, value *uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint(key, value)
+}
+
+// GetUint 获取uint类型值
+func (m *SyncKMap) GetUint(key string) (uint, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetUint(key)
+}
+
+// SetUintSlice 设置[]uint类型值
+func (m *SyncKMap) SetUintSlice(key string, value *[]uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUintSlice(key, clonePtr(value))
+}
+
+// GetUintSlice 获取[]uint类型值
+func (m *SyncKMap) GetUintSlice(key string) ([]uint, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetUintSlice(key)
+	return cloneAs(v), ok
+}
+
+// SetUint8 设置uint8类型值
+func (m *SyncKMap) SetUint8(key string, value *uint8) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint8(key, value)
+}
+
+// GetUint8 获取uint8类型值
+func (m *SyncKMap) GetUint8(key string) (uint8, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetUint8(key)
+}
+
+// SetUint8Slice 设置[]uint8类型值
+func (m *SyncKMap) SetUint8Slice(key string, value *[]uint8) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint8Slice(key, clonePtr(value))
+}
+
+// GetUint8Slice 获取[]uint8类型值
+func (m *SyncKMap) GetUint8Slice(key string) ([]uint8, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetUint8Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetUint16 设置uint16类型值
+func (m *SyncKMap) SetUint16(key string, value *uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint16(key, value)
+}
+
+// GetUint16 获取uint16类型值
+func (m *SyncKMap) GetUint16(key string) (uint16, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetUint16(key)
+}
+
+// SetUint16Slice 设置[]uint16类型值
+func (m *SyncKMap) SetUint16Slice(key string, value *[]uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint16Slice(key, clonePtr(value))
+}
+
+// GetUint16Slice 获取[]uint16类型值
+func (m *SyncKMap) GetUint16Slice(key string) ([]uint16, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetUint16Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetUint64 设置uint64类型值
+func (m *SyncKMap) SetUint64(key string, value *uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint64(key, value)
+}
+
+// GetUint64 获取uint64类型值
+func (m *SyncKMap) GetUint64(key string) (uint64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetUint64(key)
+}
+
+// SetUint64Slice 设置[]uint64类型值
+func (m *SyncKMap) SetUint64Slice(key string, value *[]uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetUint64Slice(key, clonePtr(value))
+}
+
+// GetUint64Slice 获取[]uint64类型值
+func (m *SyncKMap) GetUint64Slice(key string) ([]uint64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetUint64Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetFloat32 设置float32类型值
+func (m *SyncKMap) SetFloat32(key string, value *float32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetFloat32(key, value)
+}
+
+// GetFloat32 获取float32类型值
+func (m *SyncKMap) GetFloat32(key string) (float32, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetFloat32(key)
+}
+
+// SetFloat32Slice 设置[]float32类型值
+func (m *SyncKMap) SetFloat32Slice(key string, value *[]float32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetFloat32Slice(key, clonePtr(value))
+}
+
+// GetFloat32Slice 获取[]float32类型值
+func (m *SyncKMap) GetFloat32Slice(key string) ([]float32, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetFloat32Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetFloat64 设置float64类型值
+func (m *SyncKMap) SetFloat64(key string, value *float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetFloat64(key, value)
+}
+
+// GetFloat64 获取float64类型值
+func (m *SyncKMap) GetFloat64(key string) (float64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetFloat64(key)
+}
+
+// SetFloat64Slice 设置[]float64类型值
+func (m *SyncKMap) SetFloat64Slice(key string, value *[]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetFloat64Slice(key, clonePtr(value))
+}
+
+// GetFloat64Slice 获取[]float64类型值
+func (m *SyncKMap) GetFloat64Slice(key string) ([]float64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetFloat64Slice(key)
+	return cloneAs(v), ok
+}
+
+// SetBool 设置bool类型值
+func (m *SyncKMap) SetBool(key string, value *bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetBool(key, value)
+}
+
+// GetBool 获取bool类型值
+func (m *SyncKMap) GetBool(key string) (bool, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetBool(key)
+}
+
+// SetBoolSlice 设置[]bool类型值
+func (m *SyncKMap) SetBoolSlice(key string, value *[]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetBoolSlice(key, clonePtr(value))
+}
+
+// GetBoolSlice 获取[]bool类型值
+func (m *SyncKMap) GetBoolSlice(key string) ([]bool, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetBoolSlice(key)
+	return cloneAs(v), ok
+}
+
+// SetString 设置string类型值
+func (m *SyncKMap) SetString(key string, value *string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetString(key, value)
+}
+
+// GetString 获取string类型值
+func (m *SyncKMap) GetString(key string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetString(key)
+}
+
+// SetStringSlice 设置[]string类型值
+func (m *SyncKMap) SetStringSlice(key string, value *[]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetStringSlice(key, clonePtr(value))
+}
+
+// GetStringSlice 获取[]string类型值
+func (m *SyncKMap) GetStringSlice(key string) ([]string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetStringSlice(key)
+	return cloneAs(v), ok
+}
+
+// SetMap 设置Maps类型值
+func (m *SyncKMap) SetMap(key string, value *KMap) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetMap(key, clonePtr(value))
+}
+
+// GetMap 获取Maps类型值
+func (m *SyncKMap) GetMap(key string) (KMap, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetMap(key)
+	return cloneAs(v), ok
+}
+
+// SetMapSlice 设置[]Maps类型值
+func (m *SyncKMap) SetMapSlice(key string, value *[]KMap) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetMapSlice(key, clonePtr(value))
+}
+
+// GetMapSlice 获取[]Maps类型值
+func (m *SyncKMap) GetMapSlice(key string) ([]KMap, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetMapSlice(key)
+	return cloneAs(v), ok
+}
+
+// SetBytes 设置[]byte类型值
+func (m *SyncKMap) SetBytes(key string, value *[]byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetBytes(key, clonePtr(value))
+}
+
+// GetBytes 获取[]byte类型值
+func (m *SyncKMap) GetBytes(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data.GetBytes(key)
+	return cloneAs(v), ok
+}
+
+// Has 判断是否存在指定key
+func (m *SyncKMap) Has(key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.Has(key)
+}
+
+// Len 获取map长度
+func (m *SyncKMap) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.Len()
+}
+
+// Keys 获取所有key
+func (m *SyncKMap) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.Keys()
+}
+
+// SetSecret 设置敏感string值
+func (m *SyncKMap) SetSecret(key string, value *string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.SetSecret(key, value)
+}
+
+// GetSecret 获取敏感string值(按需解密)
+func (m *SyncKMap) GetSecret(key string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.GetSecret(key)
+}
+
+// IsSecret 判断指定key是否为敏感值
+func (m *SyncKMap) IsSecret(key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data.IsSecret(key)
+}
+
+// clonePtr 深拷贝指针指向的值
+func clonePtr[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	clone := cloneAs(*value)
+	return &clone
+}
+
+// cloneAs 深拷贝并保持原类型
+func cloneAs[T any](value T) T {
+	if clone, ok := cloneValue(value).(T); ok {
+		return clone
+	}
+	return value
+}
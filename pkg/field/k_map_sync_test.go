@@ -0,0 +1,78 @@
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSyncKMapConcurrent(t *testing.T) {
+	m := NewSyncKMap(KMap{"tags": []string{"a"}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("k%d", i)
+				n := j
+				m.SetInt(key, &n)
+				m.SetStringSlice("tags", &[]string{key})
+				if tags, ok := m.GetStringSlice("tags"); ok {
+					tags[0] = "changed" // 读出的是拷贝
+				}
+				m.Update(func(data KMap) { data["last"] = key })
+				_ = m.Snapshot()
+				if _, err := json.Marshal(m); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if m.Len() != 10 { // k0-k7 + tags + last
+		t.Fatalf("Len = %d, want 10 (keys %v)", m.Len(), m.Keys())
+	}
+	if tags, _ := m.GetStringSlice("tags"); tags[0] == "changed" {
+		t.Fatal("GetStringSlice returned shared slice")
+	}
+}
+
+func TestSyncKMapCopies(t *testing.T) {
+	src := KMap{"tags": []string{"a"}}
+	m := NewSyncKMap(src)
+	src["tags"].([]string)[0] = "src" // 创建时深拷贝
+
+	tags := []string{"b"}
+	m.SetStringSlice("set", &tags)
+	tags[0] = "set" // 写入时深拷贝
+
+	snapshot := m.Snapshot()
+	snapshot["tags"].([]string)[0] = "snapshot" // 快照与原数据隔离
+
+	for key, want := range map[string][]string{"tags": {"a"}, "set": {"b"}} {
+		if got, _ := m.GetStringSlice(key); !slices.Equal(got, want) {
+			t.Errorf("GetStringSlice(%s) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestSyncKMapJSON(t *testing.T) {
+	m := NewSyncKMap(KMap{"name": "n", "n": 1})
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := NewSyncKMap(nil)
+	if err = json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := got.GetString("name"); name != "n" {
+		t.Fatalf("GetString(name) = %q after round-trip", name)
+	}
+}
@@ -0,0 +1,74 @@
+package field
+
+import (
+	"reflect"
+	"testing"
+)
+
+type cloneInner struct {
+	Tags []string
+}
+
+type cloneOuter struct {
+	Name  string
+	Inner *cloneInner
+	Items []cloneInner
+	Attrs map[string][]int
+}
+
+func TestKMapClone(t *testing.T) {
+	src := KMap{
+		"str":    "a",
+		"slice":  []string{"x", "y"},
+		"nested": KMap{"list": []any{1, KMap{"k": "v"}}},
+		"ptr":    &cloneInner{Tags: []string{"p"}},
+		"struct": cloneOuter{
+			Name:  "o",
+			Inner: &cloneInner{Tags: []string{"i"}},
+			Items: []cloneInner{{Tags: []string{"e"}}},
+			Attrs: map[string][]int{"n": {1}},
+		},
+		"secret": NewSecret("pwd"),
+	}
+	clone := src.Clone()
+	if !reflect.DeepEqual(src, clone) {
+		t.Fatalf("clone not equal:\n%#v\n%#v", src, clone)
+	}
+
+	// 修改拷贝，原值不受影响
+	clone["slice"].([]string)[0] = "changed"
+	clone["nested"].(KMap)["list"].([]any)[1].(KMap)["k"] = "changed"
+	clone["ptr"].(*cloneInner).Tags[0] = "changed"
+	st := clone["struct"].(cloneOuter)
+	st.Inner.Tags[0] = "changed"
+	st.Items[0].Tags[0] = "changed"
+	st.Attrs["n"][0] = 2
+
+	checks := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"slice", src["slice"].([]string)[0], "x"},
+		{"nested", src["nested"].(KMap)["list"].([]any)[1].(KMap)["k"], "v"},
+		{"ptr", src["ptr"].(*cloneInner).Tags[0], "p"},
+		{"struct.ptr", src["struct"].(cloneOuter).Inner.Tags[0], "i"},
+		{"struct.slice", src["struct"].(cloneOuter).Items[0].Tags[0], "e"},
+		{"struct.map", src["struct"].(cloneOuter).Attrs["n"][0], 1},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: source changed to %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestKMapCloneNil(t *testing.T) {
+	var m KMap
+	if m.Clone() != nil {
+		t.Fatal("nil KMap clone should be nil")
+	}
+	if c := (KMap{}).Clone(); c == nil || len(c) != 0 {
+		t.Fatalf("empty KMap clone = %#v", c)
+	}
+}
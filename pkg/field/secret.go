@@ -0,0 +1,484 @@
+package field
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"sync"
+)
+
+const (
+	SecretMask      = "******"  // 脱敏显示
+	secretSealedKey = "$secret" // 序列化时密文的包装键 {"$secret": base64}
+)
+
+var (
+	secretCipher   SecretCipher
+	secretCipherMu sync.RWMutex
+	secretKeys     sync.Map // 声明的敏感键 string -> struct{}
+
+	ErrSecretCipherMissing = errors.New("secret cipher not configured")
+)
+
+// SecretCipher 敏感值加解密器
+type SecretCipher interface {
+	Encrypt(plain []byte) ([]byte, error)
+	Decrypt(sealed []byte) ([]byte, error)
+}
+
+// SetSecretCipher 设置全局敏感值加解密器(启动时调用)
+func SetSecretCipher(c SecretCipher) {
+	secretCipherMu.Lock()
+	defer secretCipherMu.Unlock()
+	secretCipher = c
+}
+
+func getSecretCipher() (SecretCipher, error) {
+	secretCipherMu.RLock()
+	defer secretCipherMu.RUnlock()
+	if secretCipher == nil {
+		return nil, ErrSecretCipherMissing
+	}
+	return secretCipher, nil
+}
+
+// RegisterSecretKeys 声明敏感键(Extra 顶层键，全局生效，在 init 中调用)
+// 声明的键无论值类型都加密序列化，读出的明文(加密前的历史数据)自动视为敏感值，下次保存时加密
+func RegisterSecretKeys(keys ...string) {
+	for _, key := range keys {
+		secretKeys.Store(key, struct{}{})
+	}
+}
+
+// IsSecretKey 是否为声明的敏感键
+func IsSecretKey(key string) bool {
+	_, ok := secretKeys.Load(key)
+	return ok
+}
+
+// Secret 敏感值，按需解密
+// JSON/二进制序列化为密文(缓存/队列/入库无损)，字符串/日志输出脱敏，响应使用 KMap.Redacted
+type Secret struct {
+	plain  []byte // 明文(新设置的值)
+	sealed []byte // 密文(从存储读出的值)
+}
+
+// NewSecret 由明文创建敏感值
+func NewSecret(plain string) Secret {
+	return Secret{plain: []byte(plain)}
+}
+
+// Reveal 获取明文(必要时解密)
+func (s Secret) Reveal() (string, error) {
+	if s.plain != nil {
+		return string(s.plain), nil
+	}
+	c, err := getSecretCipher()
+	if err != nil {
+		return "", err
+	}
+	plain, err := c.Decrypt(s.sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// Seal 获取密文(必要时加密)
+func (s Secret) Seal() ([]byte, error) {
+	if s.sealed != nil {
+		return s.sealed, nil
+	}
+	c, err := getSecretCipher()
+	if err != nil {
+		return nil, err
+	}
+	return c.Encrypt(s.plain)
+}
+
+// IsSealed 是否已加密(从存储读出的值)
+func (s Secret) IsSealed() bool {
+	return s.sealed != nil
+}
+
+func (s Secret) String() string {
+	return SecretMask
+}
+
+func (s Secret) GoString() string {
+	return SecretMask
+}
+
+// LogValue slog 脱敏
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(SecretMask)
+}
+
+// MarshalJSON 序列化为密文包装 {"$secret": base64}，未配置加密器时返回错误
+func (s Secret) MarshalJSON() ([]byte, error) {
+	sealed, err := sealValue(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed)
+}
+
+// UnmarshalJSON 反序列化密文包装(不解密)
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	var wrapper map[string]any
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return err
+	}
+	opened, err := openValue(wrapper)
+	if err != nil {
+		return err
+	}
+	secret, ok := opened.(Secret)
+	if !ok {
+		return errors.New("secret json must be sealed {\"" + secretSealedKey + "\": base64}")
+	}
+	*s = secret
+	return nil
+}
+
+// SetSecret 设置敏感string值
+func (m KMap) SetSecret(key string, value *string) {
+	if value == nil {
+		delete(m, key)
+		return
+	}
+	m[key] = NewSecret(*value)
+}
+
+// GetSecret 获取敏感string值(按需解密，兼容未加密的历史明文)
+func (m KMap) GetSecret(key string) (string, bool) {
+	switch val := m[key].(type) {
+	case Secret:
+		if plain, err := val.Reveal(); err == nil {
+			return plain, true
+		}
+	case string:
+		return val, true
+	}
+	return "", false
+}
+
+// IsSecret 判断指定key是否为敏感值(敏感值类型或声明的敏感键)
+func (m KMap) IsSecret(key string) bool {
+	if _, ok := m[key].(Secret); ok {
+		return true
+	}
+	_, exists := m[key]
+	return exists && IsSecretKey(key)
+}
+
+// UnsealedSecrets 未加密的敏感键(新设置的值/历史明文)，迁移时据此判断是否需要重新保存
+func (m KMap) UnsealedSecrets() []string {
+	var keys []string
+	for k, v := range m {
+		if secret, ok := v.(Secret); ok && !secret.IsSealed() {
+			keys = append(keys, k)
+		} else if !ok && IsSecretKey(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Redacted 响应视图: 深拷贝并将全部敏感值替换为 SecretMask
+// 返回普通map(嵌套KMap同样转换)，序列化时不再按敏感键加密
+func (m KMap) Redacted() map[string]any {
+	if m == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(m))
+	for k, v := range m {
+		if IsSecretKey(k) {
+			redacted[k] = SecretMask
+			continue
+		}
+		redacted[k] = redactValue(v)
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch val := value.(type) {
+	case Secret:
+		return SecretMask
+	case KMap:
+		return val.Redacted()
+	case map[string]any:
+		return KMap(val).Redacted()
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = redactValue(item)
+		}
+		return items
+	case []KMap:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = item.Redacted()
+		}
+		return items
+	}
+	return cloneValue(value)
+}
+
+// MarshalJSON 序列化(敏感值加密为密文包装，用于入库/缓存/队列，响应使用 Redacted)
+func (m KMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	sealed, err := sealMap(withSecretKeys(m))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealed)
+}
+
+// Value 实现 driver.Valuer，入库时敏感值加密
+func (m KMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	bytes, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// Scan 实现 sql.Scanner，出库时敏感值保持密文，按需解密
+func (m *KMap) Scan(src any) error {
+	var bytes []byte
+	switch val := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		bytes = val
+	case string:
+		bytes = []byte(val)
+	default:
+		return fmt.Errorf("kmap scan unsupported type %T", src)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return err
+	}
+	opened, err := openMap(data)
+	if err != nil {
+		return err
+	}
+	*m = opened
+	return nil
+}
+
+// sealValue 递归将 Secret 替换为密文包装
+func sealValue(value any) (any, error) {
+	switch val := value.(type) {
+	case Secret:
+		sealed, err := val.Seal()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{secretSealedKey: base64.StdEncoding.EncodeToString(sealed)}, nil
+	case KMap:
+		return sealMap(val)
+	case map[string]any:
+		return sealMap(val)
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			sealed, err := sealValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = sealed
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+// withSecretKeys 声明的敏感键值为明文时转为 Secret(无需转换时返回原map)
+func withSecretKeys(m KMap) KMap {
+	converted, cloned := m, false
+	for k, v := range m {
+		if plain, ok := v.(string); ok && IsSecretKey(k) {
+			if !cloned {
+				converted, cloned = maps.Clone(m), true
+			}
+			converted[k] = NewSecret(plain)
+		}
+	}
+	return converted
+}
+
+// openSecretKeys 声明的敏感键值为明文时(历史数据)转为 Secret，原地修改
+func openSecretKeys(m KMap) {
+	for k, v := range m {
+		if plain, ok := v.(string); ok && IsSecretKey(k) {
+			m[k] = NewSecret(plain)
+		}
+	}
+}
+
+func sealMap(m map[string]any) (map[string]any, error) {
+	sealed := make(map[string]any, len(m))
+	for k, v := range m {
+		item, err := sealValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("kmap seal key %q: %w", k, err)
+		}
+		sealed[k] = item
+	}
+	return sealed, nil
+}
+
+// openMap 还原密文包装，声明的敏感键为明文时(历史数据)转为 Secret
+func openMap(data map[string]any) (KMap, error) {
+	if data == nil {
+		return nil, nil
+	}
+	opened, err := openValue(data)
+	if err != nil {
+		return nil, err
+	}
+	m := KMap(opened.(map[string]any))
+	openSecretKeys(m)
+	return m, nil
+}
+
+// openValue 递归将密文包装还原为 Secret(不解密)
+func openValue(value any) (any, error) {
+	switch val := value.(type) {
+	case map[string]any:
+		if len(val) == 1 {
+			if encoded, ok := val[secretSealedKey].(string); ok {
+				sealed, err := base64.StdEncoding.DecodeString(encoded)
+				if err != nil {
+					return nil, err
+				}
+				return Secret{sealed: sealed}, nil
+			}
+		}
+		for k, v := range val {
+			item, err := openValue(v)
+			if err != nil {
+				return nil, err
+			}
+			val[k] = item
+		}
+		return val, nil
+	case []any:
+		for i, v := range val {
+			item, err := openValue(v)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = item
+		}
+		return val, nil
+	}
+	return value, nil
+}
+
+// EnvelopeCipher 信封加密: 每个值随机生成数据密钥(DEK)加密，DEK再由主密钥(KEK)加密后与密文一同保存
+// 格式: | 1位版本 | 12位KEK随机数 | 48位加密DEK | 12位DEK随机数 | 密文 |
+type EnvelopeCipher struct {
+	kek cipher.AEAD
+}
+
+const (
+	envelopeVersion = 1
+	envelopeDEKSize = 32
+	envelopeHeader  = 1 + 12 + envelopeDEKSize + 16 + 12
+)
+
+// NewEnvelopeCipher 创建信封加密器，kek 长度需为 16/24/32
+func NewEnvelopeCipher(kek []byte) (*EnvelopeCipher, error) {
+	aead, err := newAESGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	return &EnvelopeCipher{kek: aead}, nil
+}
+
+func (c *EnvelopeCipher) Encrypt(plain []byte) ([]byte, error) {
+	dek := make([]byte, envelopeDEKSize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	dekAEAD, err := newAESGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 1, envelopeHeader+len(plain)+dekAEAD.Overhead())
+	out[0] = envelopeVersion
+
+	// 加密DEK
+	kekNonce := make([]byte, c.kek.NonceSize())
+	if _, err = rand.Read(kekNonce); err != nil {
+		return nil, err
+	}
+	out = append(out, kekNonce...)
+	out = c.kek.Seal(out, kekNonce, dek, nil)
+
+	// 加密数据
+	dekNonce := make([]byte, dekAEAD.NonceSize())
+	if _, err = rand.Read(dekNonce); err != nil {
+		return nil, err
+	}
+	out = append(out, dekNonce...)
+	return dekAEAD.Seal(out, dekNonce, plain, nil), nil
+}
+
+func (c *EnvelopeCipher) Decrypt(sealed []byte) ([]byte, error) {
+	if len(sealed) < envelopeHeader {
+		return nil, errors.New("envelope sealed data too short")
+	}
+	if sealed[0] != envelopeVersion {
+		return nil, fmt.Errorf("envelope version %d unsupported", sealed[0])
+	}
+
+	// 解密DEK
+	offset := 1
+	kekNonce := sealed[offset : offset+12]
+	offset += 12
+	dek, err := c.kek.Open(nil, kekNonce, sealed[offset:offset+envelopeDEKSize+16], nil)
+	if err != nil {
+		return nil, err
+	}
+	offset += envelopeDEKSize + 16
+
+	// 解密数据
+	dekAEAD, err := newAESGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	dekNonce := sealed[offset : offset+12]
+	offset += 12
+	return dekAEAD.Open(nil, dekNonce, sealed[offset:], nil)
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,139 @@
+package field
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testSecretKey = "testPwd"
+
+func init() {
+	RegisterSecretKeys(testSecretKey)
+}
+
+// withTestCipher 测试期间配置加密器
+func withTestCipher(t *testing.T) {
+	t.Helper()
+	c, err := NewEnvelopeCipher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetSecretCipher(c)
+	t.Cleanup(func() { SetSecretCipher(nil) })
+}
+
+func TestSecretJSONRoundTrip(t *testing.T) {
+	withTestCipher(t)
+
+	pwd := "p@ss"
+	src := KMap{"name": "n", "nested": KMap{"token": NewSecret("tk")}}
+	src.SetSecret(testSecretKey, &pwd)
+
+	data, err := json.Marshal(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plain := range []string{pwd, "tk", SecretMask} {
+		if strings.Contains(string(data), plain) {
+			t.Fatalf("json contains %q: %s", plain, data)
+		}
+	}
+
+	var dst KMap
+	if err = json.Unmarshal(data, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := dst.GetSecret(testSecretKey); got != pwd {
+		t.Fatalf("GetSecret = %q, want %q", got, pwd)
+	}
+	nested, _ := dst.GetMap("nested")
+	if got, _ := nested.GetSecret("token"); got != "tk" {
+		t.Fatalf("nested GetSecret = %q, want tk", got)
+	}
+	if keys := dst.UnsealedSecrets(); len(keys) != 0 {
+		t.Fatalf("UnsealedSecrets = %v, want none", keys)
+	}
+}
+
+func TestSecretDeclaredKey(t *testing.T) {
+	withTestCipher(t)
+
+	// 声明的键按键加密(值为普通字符串)
+	src := KMap{testSecretKey: "plain", "other": "plain"}
+	value, err := src.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(value.(string), "plain"); n != 1 {
+		t.Fatalf("declared key stored in plaintext: %s", value)
+	}
+
+	var dst KMap
+	if err = dst.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	if !dst.IsSecret(testSecretKey) || dst.IsSecret("other") {
+		t.Fatalf("IsSecret mismatch: %#v", dst)
+	}
+	if got, _ := dst.GetSecret(testSecretKey); got != "plain" {
+		t.Fatalf("GetSecret = %q, want plain", got)
+	}
+}
+
+func TestSecretLegacyPlaintext(t *testing.T) {
+	withTestCipher(t)
+
+	// 加密前的历史数据: 读出为未加密的敏感值，重新保存后加密
+	var m KMap
+	if err := m.Scan(`{"` + testSecretKey + `":"old","name":"n"}`); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := m.GetSecret(testSecretKey); got != "old" {
+		t.Fatalf("GetSecret = %q, want old", got)
+	}
+	if keys := m.UnsealedSecrets(); len(keys) != 1 || keys[0] != testSecretKey {
+		t.Fatalf("UnsealedSecrets = %v", keys)
+	}
+
+	value, err := m.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(value.(string), "old") {
+		t.Fatalf("resaved value still plaintext: %s", value)
+	}
+}
+
+func TestSecretWithoutCipher(t *testing.T) {
+	SetSecretCipher(nil)
+
+	if _, err := json.Marshal(KMap{testSecretKey: "x"}); err == nil {
+		t.Fatal("marshal without cipher should fail instead of leaking")
+	}
+	// 明文读取不依赖加密器
+	if got, ok := (KMap{testSecretKey: NewSecret("x")}).GetSecret(testSecretKey); !ok || got != "x" {
+		t.Fatalf("GetSecret = %q,%v", got, ok)
+	}
+}
+
+func TestKMapRedacted(t *testing.T) {
+	src := KMap{
+		testSecretKey: "plain",
+		"name":        "n",
+		"nested":      KMap{"token": NewSecret("tk")},
+		"list":        []any{NewSecret("a"), "b"},
+	}
+	redacted := src.Redacted()
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"list":["******","b"],"name":"n","nested":{"token":"******"},"testPwd":"******"}`
+	if string(data) != want {
+		t.Fatalf("redacted = %s, want %s", data, want)
+	}
+	if got, _ := src.GetSecret(testSecretKey); got != "plain" {
+		t.Fatal("Redacted modified source")
+	}
+}
@@ -0,0 +1,330 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PluralFunc 复数规则: 数量 -> 复数形式
+type PluralFunc func(n float64) PluralForm
+
+// Bundle 多语言消息目录集合
+type Bundle struct {
+	mu       sync.RWMutex
+	fallback string                // 默认语言(找不到时回退)
+	catalogs map[string]Catalog    // 语言 -> 消息目录
+	plurals  map[string]PluralFunc // 基础语言 -> 复数规则
+}
+
+// NewBundle 创建消息目录集合，fallback 为默认语言
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		fallback: normalizeLang(fallback),
+		catalogs: map[string]Catalog{},
+		plurals: map[string]PluralFunc{
+			"zh": pluralNone,
+			"ja": pluralNone,
+			"ko": pluralNone,
+		},
+	}
+}
+
+// AddCatalog 添加(合并)语言的消息目录
+func (b *Bundle) AddCatalog(lang string, catalog Catalog) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lang = normalizeLang(lang)
+	if b.catalogs[lang] == nil {
+		b.catalogs[lang] = make(Catalog, len(catalog))
+	}
+	for key, msg := range catalog {
+		b.catalogs[lang][key] = msg
+	}
+}
+
+// SetPlural 设置语言的复数规则
+func (b *Bundle) SetPlural(lang string, fn PluralFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.plurals[baseLang(normalizeLang(lang))] = fn
+}
+
+// LoadDir 加载目录下所有 <语言>.json / <语言>.toml 消息目录
+func (b *Bundle) LoadDir(dir string) error {
+	return b.LoadFS(os.DirFS(dir), ".")
+}
+
+// LoadFS 加载文件系统(支持embed)目录下所有 <语言>.json / <语言>.toml 消息目录
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		if ext != ".json" && ext != ".toml" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err = b.LoadBytes(strings.TrimSuffix(entry.Name(), ext), ext, data); err != nil {
+			return fmt.Errorf("i18n load %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// LoadBytes 加载消息目录，format 为 .json / .toml
+func (b *Bundle) LoadBytes(lang, format string, data []byte) error {
+	var catalog Catalog
+	var err error
+	switch format {
+	case ".json", "json":
+		catalog, err = ParseJSON(data)
+	case ".toml", "toml":
+		catalog, err = ParseTOML(data)
+	default:
+		err = fmt.Errorf("i18n format %q unsupported", format)
+	}
+	if err != nil {
+		return err
+	}
+	b.AddCatalog(lang, catalog)
+	return nil
+}
+
+// Langs 已加载的语言
+func (b *Bundle) Langs() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	langs := make([]string, 0, len(b.catalogs))
+	for lang := range b.catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Catalog 获取语言的消息目录(只读)
+func (b *Bundle) Catalog(lang string) (Catalog, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	catalog, ok := b.catalogs[normalizeLang(lang)]
+	return catalog, ok
+}
+
+// Localizer 根据 Accept-Language 创建本地化器
+func (b *Bundle) Localizer(acceptLanguage string) *Localizer {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// 语言回退链: 请求语言(按q排序) -> 基础语言 -> 默认语言
+	var langs []string
+	add := func(lang string) {
+		if _, ok := b.catalogs[lang]; ok && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	for _, lang := range ParseAcceptLanguage(acceptLanguage) {
+		add(lang)
+		add(baseLang(lang))
+	}
+	add(b.fallback)
+	add(baseLang(b.fallback))
+	return &Localizer{bundle: b, langs: langs}
+}
+
+// Localizer 本地化器(已确定语言回退链)
+type Localizer struct {
+	bundle *Bundle
+	langs  []string
+}
+
+// Lang 当前使用的语言
+func (l *Localizer) Lang() string {
+	if len(l.langs) == 0 {
+		return l.bundle.fallback
+	}
+	return l.langs[0]
+}
+
+// Key 消息键参数，翻译时先被翻译(普通字符串参数原样输出，避免用户输入恰好是消息键时被替换)
+type Key string
+
+// Translate 翻译消息，params 为位置参数 {0} {1}...
+// Key 类型的参数会先被翻译(找不到时原样输出)，第一个数值参数决定复数形式，找不到消息时返回 key
+func (l *Localizer) Translate(key string, params ...any) string {
+	msg, lang, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+
+	args := make([]any, len(params))
+	var count *float64
+	for i, param := range params {
+		args[i] = param
+		switch val := param.(type) {
+		case Key:
+			args[i] = string(val)
+			if m, _, ok := l.lookup(string(val)); ok {
+				args[i] = m[PluralOther]
+			}
+		default:
+			if n, ok := toFloat(param); ok && count == nil {
+				count = &n
+			}
+		}
+	}
+
+	text := msg[PluralOther]
+	if count != nil && len(msg) > 1 {
+		if t, ok := msg[l.plural(lang)(*count)]; ok {
+			text = t
+		}
+	}
+	return format(text, args)
+}
+
+func (l *Localizer) lookup(key string) (Message, string, bool) {
+	l.bundle.mu.RLock()
+	defer l.bundle.mu.RUnlock()
+	for _, lang := range l.langs {
+		if msg, ok := l.bundle.catalogs[lang][key]; ok {
+			return msg, lang, true
+		}
+	}
+	return nil, "", false
+}
+
+func (l *Localizer) plural(lang string) PluralFunc {
+	l.bundle.mu.RLock()
+	defer l.bundle.mu.RUnlock()
+	if fn, ok := l.bundle.plurals[baseLang(lang)]; ok {
+		return fn
+	}
+	return pluralOneOther
+}
+
+// ParseAcceptLanguage 解析 Accept-Language，按权重排序返回语言
+func ParseAcceptLanguage(header string) []string {
+	type langQ struct {
+		lang string
+		q    float64
+	}
+	var items []langQ
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = normalizeLang(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			items = append(items, langQ{lang: lang, q: q})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].q > items[j].q })
+
+	langs := make([]string, len(items))
+	for i, item := range items {
+		langs[i] = item.lang
+	}
+	return langs
+}
+
+// format 替换位置参数 {0} {1}...
+func format(text string, args []any) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		sb.WriteString(text[:start])
+		if i, err := strconv.Atoi(text[start+1 : end]); err == nil && i >= 0 && i < len(args) {
+			sb.WriteString(fmt.Sprint(args[i]))
+		} else {
+			sb.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+	sb.WriteString(text)
+	return sb.String()
+}
+
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+func baseLang(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return base
+}
+
+func toFloat(value any) (float64, bool) {
+	switch val := value.(type) {
+	case int:
+		return float64(val), true
+	case int8:
+		return float64(val), true
+	case int16:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint:
+		return float64(val), true
+	case uint8:
+		return float64(val), true
+	case uint16:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case float32:
+		return float64(val), true
+	case float64:
+		return val, true
+	}
+	return 0, false
+}
+
+// pluralOneOther 英语等: 1 为单数
+func pluralOneOther(n float64) PluralForm {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralNone 中文等: 无复数
+func pluralNone(float64) PluralForm {
+	return PluralOther
+}
@@ -0,0 +1,117 @@
+package i18n
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTranslateParams(t *testing.T) {
+	b := NewBundle("en")
+	if err := b.LoadBytes("en", "json", []byte(`{
+		"name_err": "{0} is invalid: {1}",
+		"org_name": "Organization name",
+		"team": "Team"
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+	l := b.Localizer("en")
+
+	tests := []struct {
+		name   string
+		params []any
+		want   string
+	}{
+		{"key params translated", []any{Key("org_name"), Key("team")}, "Organization name is invalid: Team"},
+		{"plain string kept", []any{Key("org_name"), "team"}, "Organization name is invalid: team"},
+		{"unknown key kept", []any{Key("unknown"), 1}, "unknown is invalid: 1"},
+	}
+	for _, tt := range tests {
+		if got := l.Translate("name_err", tt.params...); got != tt.want {
+			t.Errorf("%s: Translate = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTranslatePlural(t *testing.T) {
+	b := NewBundle("en")
+	items := Message{PluralOne: "{0} item", PluralOther: "{0} items"}
+	b.AddCatalog("en", Catalog{"items": items})
+	b.AddCatalog("zh", Catalog{"items": {PluralOther: "{0}个"}})
+	b.AddCatalog("ru", Catalog{"items": {PluralOne: "{0} one", PluralFew: "{0} few", PluralOther: "{0} other"}})
+	b.SetPlural("ru", func(n float64) PluralForm {
+		switch {
+		case n == 1:
+			return PluralOne
+		case n >= 2 && n <= 4:
+			return PluralFew
+		}
+		return PluralOther
+	})
+
+	tests := []struct {
+		lang   string
+		params []any
+		want   string
+	}{
+		{"en", []any{1}, "1 item"},
+		{"en", []any{2}, "2 items"},
+		{"en", []any{0}, "0 items"},
+		{"en", []any{int64(1)}, "1 item"},
+		{"en", []any{1.5}, "1.5 items"},
+		{"en", []any{"1"}, "1 items"}, // 字符串不决定复数形式
+		{"en", nil, "{0} items"},
+		{"zh", []any{1}, "1个"},
+		{"ru-RU", []any{3}, "3 few"}, // 地区语言使用基础语言的复数规则
+		{"ru", []any{5}, "5 other"},
+	}
+	for _, tt := range tests {
+		if got := b.Localizer(tt.lang).Translate("items", tt.params...); got != tt.want {
+			t.Errorf("%s Translate(items, %v) = %q, want %q", tt.lang, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"en", []string{"en"}},
+		{"zh-CN,zh;q=0.9,en;q=0.8", []string{"zh-cn", "zh", "en"}},
+		{"en;q=0.5, fr;q=0.9, de", []string{"de", "fr", "en"}},
+		{"fr;q=0.8, en;q=0.8", []string{"fr", "en"}}, // 权重相同保持顺序
+		{"*, en;q=0.5", []string{"en"}},              // * 由默认语言兜底
+		{"en;q=0, fr", []string{"fr"}},               // q=0 表示不接受
+		{"zh_TW; q=0.7, EN", []string{"en", "zh-tw"}},
+		{"en;q=abc", []string{"en"}}, // 无效权重视为 1
+	}
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizerFallback(t *testing.T) {
+	b := NewBundle("en")
+	b.AddCatalog("en", Catalog{"hello": {PluralOther: "Hello"}, "bye": {PluralOther: "Bye"}})
+	b.AddCatalog("zh", Catalog{"hello": {PluralOther: "你好"}})
+
+	tests := []struct {
+		header, lang, hello, bye string
+	}{
+		{"zh-CN", "zh", "你好", "Bye"}, // 地区 -> 基础语言，缺失的键回退默认语言
+		{"fr, zh;q=0.5", "zh", "你好", "Bye"},
+		{"fr", "en", "Hello", "Bye"},
+		{"*", "en", "Hello", "Bye"},
+		{"zh;q=0, en", "en", "Hello", "Bye"},
+	}
+	for _, tt := range tests {
+		l := b.Localizer(tt.header)
+		if l.Lang() != tt.lang || l.Translate("hello") != tt.hello || l.Translate("bye") != tt.bye {
+			t.Errorf("Localizer(%q): lang %s hello %q bye %q, want %s %q %q",
+				tt.header, l.Lang(), l.Translate("hello"), l.Translate("bye"), tt.lang, tt.hello, tt.bye)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PluralForm 复数形式(CLDR)
+type PluralForm string
+
+const (
+	PluralZero  PluralForm = "zero"
+	PluralOne   PluralForm = "one"
+	PluralTwo   PluralForm = "two"
+	PluralFew   PluralForm = "few"
+	PluralMany  PluralForm = "many"
+	PluralOther PluralForm = "other"
+)
+
+// Message 消息文本，单数形式只有 other
+type Message map[PluralForm]string
+
+// Catalog 单个语言的消息目录 key -> Message
+type Catalog map[string]Message
+
+// ParseJSON 解析json消息目录
+//
+//	{"key": "文本{0}", "items": {"one": "{0} item", "other": "{0} items"}}
+func ParseJSON(data []byte) (Catalog, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	catalog := make(Catalog, len(raw))
+	for key, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			catalog[key] = Message{PluralOther: text}
+			continue
+		}
+		var forms map[PluralForm]string
+		if err := json.Unmarshal(value, &forms); err != nil {
+			return nil, fmt.Errorf("i18n json key %q: %w", key, err)
+		}
+		if err := checkForms(key, forms); err != nil {
+			return nil, err
+		}
+		catalog[key] = forms
+	}
+	return catalog, nil
+}
+
+// ParseTOML 解析toml消息目录(仅支持字符串键值与单层表，支持行尾注释，重复的键/表返回错误)
+//
+//	key = "文本{0}"
+//	[items]
+//	one = "{0} item"
+//	other = "{0} items"
+func ParseTOML(data []byte) (Catalog, error) {
+	catalog := make(Catalog)
+	table := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// [table]
+		if strings.HasPrefix(text, "[") {
+			end := strings.IndexByte(text, ']')
+			if end < 0 || !isComment(text[end+1:]) {
+				return nil, fmt.Errorf("i18n toml line %d: invalid table", line)
+			}
+			table = unquoteKey(strings.TrimSpace(text[1:end]))
+			if _, ok := catalog[table]; ok {
+				return nil, fmt.Errorf("i18n toml line %d: duplicate key %q", line, table)
+			}
+			catalog[table] = Message{}
+			continue
+		}
+
+		// key = "value"
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("i18n toml line %d: expected key = value", line)
+		}
+		key = unquoteKey(strings.TrimSpace(key))
+		str, err := unquoteValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("i18n toml line %d: %w", line, err)
+		}
+
+		if table == "" {
+			if _, ok := catalog[key]; ok {
+				return nil, fmt.Errorf("i18n toml line %d: duplicate key %q", line, key)
+			}
+			catalog[key] = Message{PluralOther: str}
+		} else {
+			if _, ok := catalog[table][PluralForm(key)]; ok {
+				return nil, fmt.Errorf("i18n toml line %d: duplicate key %q in table %q", line, key, table)
+			}
+			catalog[table][PluralForm(key)] = str
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for key, forms := range catalog {
+		if err := checkForms(key, forms); err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
+
+// checkForms 检查复数形式，必须包含 other
+func checkForms(key string, forms Message) error {
+	if _, ok := forms[PluralOther]; !ok {
+		return fmt.Errorf("i18n key %q missing plural form %q", key, PluralOther)
+	}
+	for form := range forms {
+		switch form {
+		case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		default:
+			return fmt.Errorf("i18n key %q unknown plural form %q", key, form)
+		}
+	}
+	return nil
+}
+
+func unquoteKey(key string) string {
+	if k, err := unquoteValue(key); err == nil {
+		return k
+	}
+	return key
+}
+
+func unquoteValue(value string) (string, error) {
+	// 去掉行尾注释
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, `'`) {
+		quote := value[0]
+		for i := 1; i < len(value); i++ {
+			if value[i] == '\\' && quote == '"' {
+				i++
+				continue
+			}
+			if value[i] == quote {
+				if !isComment(value[i+1:]) {
+					return "", fmt.Errorf("unexpected %s after string", strings.TrimSpace(value[i+1:]))
+				}
+				value = value[:i+1]
+				break
+			}
+		}
+	}
+
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		return strconv.Unquote(value)
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1], nil // 字面量字符串不转义
+	}
+	return "", fmt.Errorf("invalid string %s", value)
+}
+
+// isComment 是否为空或行尾注释
+func isComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || strings.HasPrefix(rest, "#")
+}
@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Catalog
+		err  string // 错误包含的文本，空时期望成功
+	}{
+		{"key value", `name = "Name {0}"`, Catalog{"name": {PluralOther: "Name {0}"}}, ""},
+		{"literal string", `path = 'C:\dir'`, Catalog{"path": {PluralOther: `C:\dir`}}, ""},
+		{"escapes", `quote = "say \"hi\"\n"`, Catalog{"quote": {PluralOther: "say \"hi\"\n"}}, ""},
+		{"quoted key", `"org.name" = "Org"`, Catalog{"org.name": {PluralOther: "Org"}}, ""},
+		{"comments", "# comment\n\nname = \"Name\" # inline # more\n[items] # table\nother = \"# not comment\"",
+			Catalog{"name": {PluralOther: "Name"}, "items": {PluralOther: "# not comment"}}, ""},
+		{"table", "[items]\none = \"{0} item\"\nother = \"{0} items\"",
+			Catalog{"items": {PluralOne: "{0} item", PluralOther: "{0} items"}}, ""},
+		{"quoted table", "[\"a.b\"]\nother = \"x\"", Catalog{"a.b": {PluralOther: "x"}}, ""},
+		{"duplicate key", "name = \"a\"\nname = \"b\"", nil, `duplicate key "name"`},
+		{"duplicate table", "[items]\nother = \"a\"\n[items]\nother = \"b\"", nil, `duplicate key "items"`},
+		{"key then table", "items = \"a\"\n[items]\nother = \"b\"", nil, `duplicate key "items"`},
+		{"duplicate form", "[items]\nother = \"a\"\nother = \"b\"", nil, `duplicate key "other" in table "items"`},
+		{"missing other", "[items]\none = \"a\"", nil, `missing plural form "other"`},
+		{"unknown form", "[items]\nsome = \"a\"\nother = \"b\"", nil, `unknown plural form "some"`},
+		{"trailing text", `name = "a" b`, nil, "unexpected b"},
+		{"unquoted value", `name = a`, nil, "invalid string"},
+		{"no equals", `name`, nil, "expected key = value"},
+		{"unclosed table", `[items`, nil, "invalid table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTOML([]byte(tt.data))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("catalog = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	got, err := ParseJSON([]byte(`{"name": "Name", "items": {"one": "{0} item", "other": "{0} items"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Catalog{"name": {PluralOther: "Name"}, "items": {PluralOne: "{0} item", PluralOther: "{0} items"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("catalog = %v, want %v", got, want)
+	}
+	if _, err = ParseJSON([]byte(`{"items": {"one": "x"}}`)); err == nil {
+		t.Fatal("missing other form should fail")
+	}
+}
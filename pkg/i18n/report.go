@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Report 消息目录完整性报告
+type Report struct {
+	Missing map[string][]string // 语言 -> 缺少的键
+	Unused  map[string][]string // 语言 -> 未使用的键
+}
+
+// Check 检查各语言目录是否包含全部键，以及是否有未使用的键
+func (b *Bundle) Check(keys []string) *Report {
+	used := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		used[key] = true
+	}
+
+	report := &Report{Missing: map[string][]string{}, Unused: map[string][]string{}}
+	for _, lang := range b.Langs() {
+		catalog, _ := b.Catalog(lang)
+		for key := range used {
+			if _, ok := catalog[key]; !ok {
+				report.Missing[lang] = append(report.Missing[lang], key)
+			}
+		}
+		for key := range catalog {
+			if !used[key] {
+				report.Unused[lang] = append(report.Unused[lang], key)
+			}
+		}
+		sort.Strings(report.Missing[lang])
+		sort.Strings(report.Unused[lang])
+	}
+	return report
+}
+
+// OK 没有缺少的键
+func (r *Report) OK() bool {
+	for _, keys := range r.Missing {
+		if len(keys) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String 报告文本
+func (r *Report) String() string {
+	var sb strings.Builder
+	write := func(title string, langKeys map[string][]string) {
+		langs := make([]string, 0, len(langKeys))
+		for lang := range langKeys {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		for _, lang := range langs {
+			for _, key := range langKeys[lang] {
+				_, _ = fmt.Fprintf(&sb, "%s\t%s\t%s\n", title, lang, key)
+			}
+		}
+	}
+	write("missing", r.Missing)
+	write("unused", r.Unused)
+	return sb.String()
+}
@@ -0,0 +1,217 @@
+package valid
+
+import (
+	"github.com/go-playground/validator/v10"
+	"katydid-mp-account/pkg/field"
+	"reflect"
+	"sort"
+)
+
+// compiledPlan 类型+场景的结构体验证计划，组合类型的访问路径和规则列表在注册时预先计算
+// 声明式规则和 Rule 类型的额外规则从零值获取(与对象当前值无关)
+// 有 ValidFn 的额外规则可能读取对象当前值，验证时从当前对象重新获取
+type compiledPlan struct {
+	scene  Scene
+	units  []*planUnit   // 声明了规则的结构体(组合类型在前，外层在后)
+	scenes []Scene       // 结构体规则的场景(全局 -> 祖先场景 -> 当前场景)
+	keys   *extraKeyPlan // Extra键验证计划
+}
+
+// planUnit 结构体(或组合类型)自身声明的规则
+type planUnit struct {
+	index    []int        // 相对被验证结构体的下标(自身为nil)
+	extra    []extraCheck // 额外规则(按标签排序)
+	extraIdx []int        // Extra 字段下标(相对本结构体)，nil时调用 ValidExtraRules 获取
+	liveFn   bool         // 额外规则有 ValidFn，验证时从当前对象获取规则
+	rules    []ruleField  // 声明式规则(按字段名排序)
+	strct    bool         // 实现了 IStructValidator
+}
+
+// extraCheck 额外规则(已合并场景)
+type extraCheck struct {
+	tag  Tag
+	key  string
+	info ExtraValidRuleInfo
+}
+
+// ruleField 声明式规则(已合并场景)
+type ruleField struct {
+	name     FieldName
+	index    []int
+	jsonName string
+	rule     *Rule
+}
+
+// compilePlan 编译类型+场景的结构体验证计划
+func (v *Validator) compilePlan(typ reflect.Type, scene Scene) *compiledPlan {
+	typ = indirectType(typ)
+	plan := &compiledPlan{
+		scene:  scene,
+		scenes: append([]Scene{SceneAll}, scene.Lineage()...),
+		keys:   newExtraKeyPlan(typ, scene),
+	}
+	plan.collect(v, typ, nil, scene, map[reflect.Type]bool{})
+	return plan
+}
+
+func (p *compiledPlan) collect(v *Validator, typ reflect.Type, index []int, scene Scene, path map[reflect.Type]bool) {
+	if path[typ] {
+		return // 递归组合
+	}
+	path[typ] = true
+	defer delete(path, typ)
+
+	// 组合类型先处理(内部会递归处理组合类型的组合类型)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if embedTyp := indirectType(sf.Type); sf.Anonymous && embedTyp.Kind() == reflect.Struct {
+			p.collect(v, embedTyp, append(append([]int{}, index...), i), scene, path)
+		}
+	}
+
+	unit := &planUnit{index: index}
+	obj := reflect.New(typ).Interface()
+
+	// -- 额外验证 --
+	if ev, ok := ownImpl[IExtraValidator](obj, "ValidExtraRules"); ok {
+		_, sceneRules := ev.ValidExtraRules()
+		unit.extra = extraChecks(sceneRules, scene)
+		for _, c := range unit.extra {
+			unit.liveFn = unit.liveFn || c.info.ValidFn != nil
+		}
+		if sf, ok := typ.FieldByName(ExtraField); ok && sf.Type == reflect.TypeFor[field.KMap]() {
+			unit.extraIdx = sf.Index
+		}
+	}
+
+	// -- 声明式规则 --
+	rules := v.sceneRules(obj, scene)
+	for _, name := range sortedFieldNames(rules) {
+		if sf, ok := typ.FieldByName(string(name)); ok {
+			unit.rules = append(unit.rules, ruleField{
+				name: name, index: sf.Index, jsonName: jsonFieldName(typ, name), rule: rules[name],
+			})
+		}
+	}
+
+	// -- 结构体验证 --
+	_, unit.strct = ownImpl[IStructValidator](obj, "ValidStructRules")
+
+	if len(unit.extra) > 0 || len(unit.rules) > 0 || unit.strct {
+		p.units = append(p.units, unit)
+	}
+}
+
+// extraChecks 合并当前场景(全局 -> 祖先场景 -> 当前场景)的额外规则，按标签排序
+func extraChecks(sceneRules ExtraValidRules, scene Scene) []extraCheck {
+	tagRules := make(map[Tag]ExtraValidRuleInfo)
+	for _, s := range matchScenes(sceneRules, scene) {
+		for tag, info := range sceneRules[s] {
+			tagRules[tag] = info // 合并验证规则
+		}
+	}
+	checks := make([]extraCheck, 0, len(tagRules))
+	for tag, info := range tagRules {
+		key := info.Field // 同一键可以有多条规则(标签不同)
+		if key == "" {
+			key = string(tag)
+		}
+		checks = append(checks, extraCheck{tag: tag, key: key, info: info})
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].tag < checks[j].tag })
+	return checks
+}
+
+// valid 执行结构体级验证: 额外规则 -> Extra键 -> 声明式规则 -> 结构体规则
+func (p *compiledPlan) valid(sl validator.StructLevel) {
+	root := addrOf(sl.Current()).Elem()
+
+	for _, u := range p.units {
+		if val, ok := u.value(root); ok && len(u.extra) > 0 {
+			u.validExtra(val, p.scene, sl)
+		}
+	}
+
+	p.keys.valid(sl)
+
+	for _, u := range p.units {
+		if val, ok := u.value(root); ok && len(u.rules) > 0 {
+			u.validRules(val, sl)
+		}
+	}
+
+	for _, u := range p.units {
+		if val, ok := u.value(root); ok && u.strct {
+			sv := val.Addr().Interface().(IStructValidator)
+			report := func(field any, fieldName FieldName, tag Tag, param string) {
+				sl.ReportError(field, jsonFieldName(root.Type(), fieldName), string(fieldName), string(tag), param)
+			}
+			for _, s := range p.scenes {
+				sv.ValidStructRules(s, report)
+			}
+		}
+	}
+}
+
+// value 获取组合类型的值(指针为空时跳过)
+func (u *planUnit) value(root reflect.Value) (reflect.Value, bool) {
+	if u.index == nil {
+		return root, true
+	}
+	val, err := root.FieldByIndexErr(u.index)
+	if err != nil {
+		return val, false
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return val, false
+		}
+		val = val.Elem()
+	}
+	return val, true
+}
+
+// validExtra 执行额外验证规则
+func (u *planUnit) validExtra(val reflect.Value, scene Scene, sl validator.StructLevel) {
+	var extra field.KMap
+	checks := u.extra
+	if u.extraIdx != nil && !u.liveFn {
+		if fieldVal, err := val.FieldByIndexErr(u.extraIdx); err == nil {
+			extra = fieldVal.Interface().(field.KMap)
+		}
+	} else {
+		var sceneRules ExtraValidRules
+		extra, sceneRules = val.Addr().Interface().(IExtraValidator).ValidExtraRules()
+		if u.liveFn {
+			checks = extraChecks(sceneRules, scene) // ValidFn 闭包读取当前对象
+		}
+	}
+	if extra == nil {
+		return
+	}
+
+	for _, c := range checks {
+		value, exists := extra[c.key]
+		if (c.tag == TagRequired) && !exists {
+			reportExtraError(sl, value, c.key, string(c.tag), c.info.Param)
+			continue
+		}
+		if exists && !c.info.valid(value) {
+			reportExtraError(sl, value, c.key, string(c.tag), c.info.Param)
+		}
+	}
+}
+
+// validRules 执行声明式规则
+func (u *planUnit) validRules(val reflect.Value, sl validator.StructLevel) {
+	for i := range u.rules {
+		r := &u.rules[i]
+		fieldVal, err := val.FieldByIndexErr(r.index)
+		if err != nil {
+			continue // 组合类型指针为空
+		}
+		r.rule.check(fieldVal, "", func(value any, index string, c *ruleCheck) {
+			sl.ReportError(value, r.jsonName+index, string(r.name)+index, string(c.tag), c.param)
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package valid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultContextTimeout 上下文验证默认超时
+const DefaultContextTimeout = 3 * time.Second
+
+// DefaultBatchConcurrency 批量验证默认并发数
+const DefaultBatchConcurrency = 8
+
+// DefaultMaxContextRules 同时执行的上下文验证规则上限(含超时后仍未返回的规则)
+const DefaultMaxContextRules = 256
+
+// contextRuleSlots 上下文验证规则执行槽位，规则返回后才释放，超时未返回的规则持续占用
+var contextRuleSlots = make(chan struct{}, DefaultMaxContextRules)
+
+// SetMaxContextRules 设置同时执行的上下文验证规则上限(启动时调用)
+// 依赖(如数据库)变慢时，超时的规则仍占用槽位，新的规则等待槽位直至超时，避免协程和连接无限增长
+func SetMaxContextRules(n int) {
+	if n <= 0 {
+		n = DefaultMaxContextRules
+	}
+	contextRuleSlots = make(chan struct{}, n)
+}
+
+// ContextRulesInFlight 正在执行的上下文验证规则数(含超时后仍未返回的规则，用于监控)
+func ContextRulesInFlight() int {
+	return len(contextRuleSlots)
+}
+
+// ErrDepMissing 上下文中缺少依赖
+var ErrDepMissing = errors.New("validation dependency missing")
+
+// 上下文(依赖数据库等)验证，规则闭包读取对象当前值，因此每次验证都重新获取(不缓存)
+type (
+	IContextValidator interface {
+		ValidContextRules() ContextValidRules
+	}
+
+	ContextValidRules    = map[Scene]ContextValidRule
+	ContextValidRule     = map[Tag]ContextValidRuleInfo
+	ContextValidRuleInfo struct {
+		Field   FieldName     // 上报的字段
+		Param   string        // 标签参数
+		Timeout time.Duration // 超时，默认 DefaultContextTimeout
+		// ValidFn 必须响应 ctx 取消(数据库等调用传入 ctx)，超时后不再等待结果，但规则返回前一直占用执行槽位
+		ValidFn func(ctx context.Context) (bool, error)
+	}
+)
+
+// depKey 依赖注入键(按类型区分)
+type depKey[T any] struct{}
+
+// Provide 注入验证依赖(如仓库)，按类型区分
+func Provide[T any](ctx context.Context, dep T) context.Context {
+	return context.WithValue(ctx, depKey[T]{}, dep)
+}
+
+// Dep 获取验证依赖
+func Dep[T any](ctx context.Context) (T, error) {
+	dep, ok := ctx.Value(depKey[T]{}).(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %s", ErrDepMissing, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return dep, nil
+}
+
+// CheckCtx 根据场景执行验证(含上下文验证)，并返回本地化错误信息
+// 上下文验证并发执行，依赖失败/超时的规则视为验证失败(MsgErr.Err 为原因)
+func CheckCtx(ctx context.Context, obj any, scene Scene) (errs, warns []*MsgErr) {
+	if obj == nil {
+		return Check(obj, scene)
+	}
+
+	v := Get()
+	validate, e := v.plan(obj, scene)
+	if e != nil {
+		return []*MsgErr{{Err: e}}, nil
+	}
+
+	// -- 执行验证(有缓存) --
+	var validateErrs validator.ValidationErrors
+	if e = validate.StructCtx(ctx, obj); e != nil && !errors.As(e, &validateErrs) {
+		return splitSeverity(v.handleValidationError(obj, scene, e))
+	}
+
+	// -- 执行上下文验证 --
+	validateErrs = append(validateErrs, v.validContext(ctx, obj, scene)...)
+	if len(validateErrs) == 0 {
+		return nil, nil
+	}
+	return splitSeverity(v.handleValidationError(obj, scene, validateErrs))
+}
+
+// CheckBatchCtx 批量验证(并发)，返回与 objs 一一对应的错误/警告信息
+// ctx 取消后不再启动新的验证，未验证的对象返回取消原因
+func CheckBatchCtx(ctx context.Context, scene Scene, objs ...any) (errs, warns [][]*MsgErr) {
+	errs, warns = make([][]*MsgErr, len(objs)), make([][]*MsgErr, len(objs))
+	sem := make(chan struct{}, DefaultBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, obj := range objs {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if e := ctx.Err(); e != nil {
+			for j := i; j < len(objs); j++ {
+				errs[j] = []*MsgErr{{Err: e, Msg: MsgValidationFailed}}
+			}
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i], warns[i] = CheckCtx(ctx, obj, scene)
+		}()
+	}
+	wg.Wait()
+	return errs, warns
+}
+
+// ctxRule 待执行的上下文验证规则
+type ctxRule struct {
+	tag   Tag
+	info  ContextValidRuleInfo
+	owner reflect.Value // 声明规则的结构体
+}
+
+// validContext 并发执行上下文验证规则
+func (v *Validator) validContext(ctx context.Context, obj any, scene Scene) validator.ValidationErrors {
+	var rules []ctxRule
+	collectContextRules(obj, scene, &rules)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	rootTyp := indirectType(reflect.TypeOf(obj))
+	fieldErrs := make([]validator.FieldError, len(rules))
+	var wg sync.WaitGroup
+	for i, rule := range rules {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := runContextRule(ctx, rule.info)
+			if !ok || err != nil {
+				fieldErrs[i] = newCtxFieldError(rootTyp, rule, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var validateErrs validator.ValidationErrors
+	for _, fe := range fieldErrs {
+		if fe != nil {
+			validateErrs = append(validateErrs, fe)
+		}
+	}
+	return validateErrs
+}
+
+// runContextRule 超时执行单条规则(规则未响应取消时不再等待，执行槽位在规则返回后释放)
+func runContextRule(ctx context.Context, info ContextValidRuleInfo) (bool, error) {
+	timeout := info.Timeout
+	if timeout <= 0 {
+		timeout = DefaultContextTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	slots := contextRuleSlots
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	type result struct {
+		ok  bool
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			<-slots
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("context rule panic: %v", r)}
+			}
+		}()
+		ok, err := info.ValidFn(ctx)
+		done <- result{ok: ok, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.ok, r.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// collectContextRules 收集当前场景(全局+祖先+当前)的上下文验证规则(含组合类型)
+func collectContextRules(obj any, scene Scene, rules *[]ctxRule) {
+	val := reflect.ValueOf(obj)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+
+	// 处理嵌入字段的验证规则
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if !typ.Field(i).Anonymous {
+			continue
+		}
+		fieldVal := val.Field(i)
+		if fieldVal.Kind() == reflect.Ptr {
+			if !fieldVal.IsNil() {
+				collectContextRules(fieldVal.Interface(), scene, rules)
+			}
+		} else if fieldVal.CanAddr() {
+			collectContextRules(fieldVal.Addr().Interface(), scene, rules)
+		}
+	}
+
+	cv, ok := ownImpl[IContextValidator](obj, "ValidContextRules")
+	if !ok {
+		return
+	}
+	sceneRules := cv.ValidContextRules()
+	tagRules := make(ContextValidRule)
+	for _, key := range matchScenes(sceneRules, scene) {
+		for tag, info := range sceneRules[key] {
+			tagRules[tag] = info // 当前场景覆盖全局/祖先场景
+		}
+	}
+
+	tags := make([]Tag, 0, len(tagRules))
+	for tag := range tagRules {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	for _, tag := range tags {
+		if tagRules[tag].ValidFn != nil {
+			*rules = append(*rules, ctxRule{tag: tag, info: tagRules[tag], owner: val})
+		}
+	}
+}
+
+// ctxFieldError 上下文验证/数据库约束错误，实现 validator.FieldError 以复用本地化规则
+type ctxFieldError struct {
+	tag         string
+	param       string
+	ns          string
+	structNs    string
+	field       string
+	structField string
+	value       any
+	typ         reflect.Type
+	err         error
+}
+
+func newCtxFieldError(rootTyp reflect.Type, rule ctxRule, err error) *ctxFieldError {
+	fe := newFieldError(rule.owner, rule.info.Field, rule.tag, rule.info.Param, err)
+	fe.ns = rootTyp.Name() + "." + fe.field
+	fe.structNs = rootTyp.Name() + "." + fe.structField
+	return fe
+}
+
+// newFieldError 创建字段验证错误(owner 为字段所属结构体)
+func newFieldError(owner reflect.Value, fieldName FieldName, tag Tag, param string, err error) *ctxFieldError {
+	name := string(fieldName)
+	jsonName := jsonFieldName(owner.Type(), fieldName)
+	fe := &ctxFieldError{
+		tag:         string(tag),
+		param:       param,
+		ns:          owner.Type().Name() + "." + jsonName,
+		structNs:    owner.Type().Name() + "." + name,
+		field:       jsonName,
+		structField: name,
+		err:         err,
+	}
+	if fieldVal := owner.FieldByName(name); fieldVal.IsValid() && fieldVal.CanInterface() {
+		fe.value = fieldVal.Interface()
+		fe.typ = fieldVal.Type()
+	}
+	return fe
+}
+
+func (e *ctxFieldError) Tag() string             { return e.tag }
+func (e *ctxFieldError) ActualTag() string       { return e.tag }
+func (e *ctxFieldError) Namespace() string       { return e.ns }
+func (e *ctxFieldError) StructNamespace() string { return e.structNs }
+func (e *ctxFieldError) Field() string           { return e.field }
+func (e *ctxFieldError) StructField() string     { return e.structField }
+func (e *ctxFieldError) Value() any              { return e.value }
+func (e *ctxFieldError) Param() string           { return e.param }
+func (e *ctxFieldError) Type() reflect.Type      { return e.typ }
+func (e *ctxFieldError) Unwrap() error           { return e.err }
+
+func (e *ctxFieldError) Kind() reflect.Kind {
+	if e.typ == nil {
+		return reflect.Invalid
+	}
+	return e.typ.Kind()
+}
+
+func (e *ctxFieldError) Translate(ut.Translator) string { return e.Error() }
+
+func (e *ctxFieldError) Error() string {
+	msg := fmt.Sprintf("Key: '%s' Error:Field validation for '%s' failed on the '%s' tag", e.ns, e.field, e.tag)
+	if e.err != nil {
+		msg += ": " + e.err.Error()
+	}
+	return msg
+}
@@ -0,0 +1,69 @@
+package valid
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContextRuleSlots(t *testing.T) {
+	SetMaxContextRules(1)
+	t.Cleanup(func() { SetMaxContextRules(DefaultMaxContextRules) })
+
+	// 不响应取消的规则: 超时返回，但持续占用槽位
+	release := make(chan struct{})
+	stuck := ContextValidRuleInfo{Timeout: 20 * time.Millisecond, ValidFn: func(context.Context) (bool, error) {
+		<-release
+		return true, nil
+	}}
+	if _, err := runContextRule(context.Background(), stuck); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("stuck rule err = %v, want deadline exceeded", err)
+	}
+	if n := ContextRulesInFlight(); n != 1 {
+		t.Fatalf("in flight = %d, want 1", n)
+	}
+
+	// 槽位已满: 新规则等待至超时，不启动协程
+	var ran atomic.Bool
+	fast := ContextValidRuleInfo{Timeout: 20 * time.Millisecond, ValidFn: func(context.Context) (bool, error) {
+		ran.Store(true)
+		return true, nil
+	}}
+	if _, err := runContextRule(context.Background(), fast); !errors.Is(err, context.DeadlineExceeded) || ran.Load() {
+		t.Fatalf("rule with no free slot: err = %v, ran = %v", err, ran.Load())
+	}
+
+	// 规则返回后释放槽位
+	close(release)
+	for deadline := time.Now().Add(time.Second); ContextRulesInFlight() != 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("slot not released")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if ok, err := runContextRule(context.Background(), fast); !ok || err != nil || !ran.Load() {
+		t.Fatalf("rule after release: ok = %v, err = %v, ran = %v", ok, err, ran.Load())
+	}
+}
+
+type batchCase struct {
+	Name string
+}
+
+func TestCheckBatchCtxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	objs := make([]any, DefaultBatchConcurrency*2)
+	for i := range objs {
+		objs[i] = &batchCase{Name: "n"}
+	}
+	errs, _ := CheckBatchCtx(ctx, SceneAdd, objs...)
+	for i, msgErrs := range errs {
+		if len(msgErrs) != 1 || !errors.Is(msgErrs[0].Err, context.Canceled) {
+			t.Fatalf("errs[%d] = %v, want canceled", i, msgErrs)
+		}
+	}
+}
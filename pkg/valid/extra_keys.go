@@ -0,0 +1,208 @@
+package valid
+
+import (
+	"github.com/go-playground/validator/v10"
+	"katydid-mp-account/pkg/field"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extra键标签(额外验证错误以 extra.<key> 上报)
+const (
+	TagUnknown  Tag = "unknown"  // 未声明的键
+	TagReadOnly Tag = "readonly" // 只读键(当前场景不允许出现)
+)
+
+// ExtraPolicy 未声明Extra键的处理策略
+type ExtraPolicy uint8
+
+const (
+	ExtraInherit ExtraPolicy = 0 // 继承(全局/父场景/组合类型的策略，默认允许)
+	ExtraAllow   ExtraPolicy = 1 // 允许
+	ExtraStrip   ExtraPolicy = 2 // 删除(验证不修改对象，由 StripExtra 返回移除后的副本)
+	ExtraReject  ExtraPolicy = 3 // 拒绝(验证错误)
+)
+
+// Extra键声明，额外规则(ValidExtraRules 全部场景)中的键自动视为已声明
+type (
+	IExtraKeyValidator interface {
+		ValidExtraKeys() ExtraKeyRules
+	}
+
+	ExtraKeyRules = map[Scene]ExtraKeyRule
+	ExtraKeyRule  struct {
+		Policy   ExtraPolicy // 未声明键的策略(当前场景覆盖全局/父场景，外层覆盖组合类型)
+		Keys     []string    // 已声明的键(没有验证规则的键)
+		ReadOnly []string    // 只读键(由专用流程设置，如根密码)
+	}
+)
+
+// extraKeyPlan 类型+场景的Extra键验证计划
+type extraKeyPlan struct {
+	index    []int // Extra 字段下标
+	policy   ExtraPolicy
+	declared map[string]bool
+	readOnly map[string]bool
+}
+
+// newExtraKeyPlan 收集类型(含组合类型)的Extra键声明，没有策略和只读键时返回nil
+func newExtraKeyPlan(typ reflect.Type, scene Scene) *extraKeyPlan {
+	typ = indirectType(typ)
+	sf, ok := typ.FieldByName(ExtraField)
+	if !ok || sf.Type != reflect.TypeFor[field.KMap]() {
+		return nil
+	}
+	plan := &extraKeyPlan{index: sf.Index, declared: map[string]bool{}, readOnly: map[string]bool{}}
+	plan.collect(typ, scene, map[reflect.Type]bool{})
+	if plan.policy != ExtraStrip && plan.policy != ExtraReject && len(plan.readOnly) == 0 {
+		return nil
+	}
+	return plan
+}
+
+func (p *extraKeyPlan) collect(typ reflect.Type, scene Scene, visited map[reflect.Type]bool) {
+	if typ.Kind() != reflect.Struct || visited[typ] {
+		return
+	}
+	visited[typ] = true
+
+	// 组合类型先收集，外层覆盖
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous {
+			p.collect(indirectType(sf.Type), scene, visited)
+		}
+	}
+
+	obj := reflect.New(typ).Interface()
+	if ev, ok := ownImpl[IExtraValidator](obj, "ValidExtraRules"); ok {
+		_, sceneRules := ev.ValidExtraRules()
+		for _, tagRules := range sceneRules {
+			for tag, info := range tagRules {
+				if info.Field != "" {
+					p.declared[info.Field] = true
+				} else {
+					p.declared[string(tag)] = true
+				}
+			}
+		}
+	}
+	kv, ok := ownImpl[IExtraKeyValidator](obj, "ValidExtraKeys")
+	if !ok {
+		return
+	}
+	sceneRules := kv.ValidExtraKeys()
+	for _, rule := range sceneRules {
+		for _, key := range rule.Keys {
+			p.declared[key] = true // 全部场景声明的键
+		}
+		for _, key := range rule.ReadOnly {
+			p.declared[key] = true
+		}
+	}
+	for _, s := range matchScenes(sceneRules, scene) {
+		rule := sceneRules[s]
+		if rule.Policy != ExtraInherit {
+			p.policy = rule.Policy
+		}
+		for _, key := range rule.ReadOnly {
+			p.readOnly[key] = true
+		}
+	}
+}
+
+// valid 检查Extra键(只读键/未声明键)，只读不修改对象(策略为删除时不上报，见 StripExtra)
+func (p *extraKeyPlan) valid(sl validator.StructLevel) {
+	if p == nil {
+		return
+	}
+	fieldVal, err := sl.Current().FieldByIndexErr(p.index)
+	if err != nil {
+		return // 组合类型指针为空
+	}
+	extra := fieldVal.Interface().(field.KMap)
+	if len(extra) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch {
+		case p.readOnly[key]:
+			reportExtraError(sl, extra[key], key, string(TagReadOnly), "")
+		case p.declared[key]:
+		case p.policy == ExtraReject:
+			reportExtraError(sl, extra[key], key, string(TagUnknown), "")
+		}
+	}
+}
+
+var extraKeyPlans sync.Map // planKey -> *extraKeyPlan
+
+// StripExtra 返回移除未声明键后的 Extra 副本(场景策略为 ExtraStrip 时，否则返回原样的副本)
+// 验证不修改对象，请求绑定时显式调用: obj.Extra = valid.StripExtra(obj, valid.SceneBind)
+func StripExtra(obj any, scene Scene) field.KMap {
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	sf, ok := val.Type().FieldByName(ExtraField)
+	if !ok || sf.Type != reflect.TypeFor[field.KMap]() {
+		return nil
+	}
+	fieldVal, err := val.FieldByIndexErr(sf.Index)
+	if err != nil {
+		return nil // 组合类型指针为空
+	}
+	extra := fieldVal.Interface().(field.KMap)
+
+	key := planKey{typ: val.Type(), scene: scene}
+	plan, ok := extraKeyPlans.Load(key)
+	if !ok {
+		plan, _ = extraKeyPlans.LoadOrStore(key, newExtraKeyPlan(val.Type(), scene))
+	}
+	p := plan.(*extraKeyPlan)
+	if extra == nil || p == nil || p.policy != ExtraStrip {
+		return maps.Clone(extra)
+	}
+	stripped := make(field.KMap, len(extra))
+	for k, v := range extra {
+		if p.declared[k] || p.readOnly[k] {
+			stripped[k] = v
+		}
+	}
+	return stripped
+}
+
+// appendExtraKeyErrs Extra键错误自动生成本地化信息 {键}
+func appendExtraKeyErrs(msgErrs []*MsgErr, validateErrs validator.ValidationErrors) []*MsgErr {
+	handled := make(map[validator.FieldError]bool, len(msgErrs))
+	for _, msgErr := range msgErrs {
+		handled[msgErr.fe] = true
+	}
+	for _, ee := range validateErrs {
+		key, ok := strings.CutPrefix(ee.StructField(), ExtraField+".")
+		if handled[ee] || !ok {
+			continue
+		}
+		switch Tag(ee.Tag()) {
+		case TagUnknown:
+			msgErrs = append(msgErrs, &MsgErr{Msg: MsgExtraUnknown, Params: []any{key}, fe: ee})
+		case TagReadOnly:
+			msgErrs = append(msgErrs, &MsgErr{Msg: MsgExtraReadOnly, Params: []any{key}, fe: ee})
+		}
+	}
+	return msgErrs
+}
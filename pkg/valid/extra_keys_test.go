@@ -0,0 +1,67 @@
+package valid
+
+import (
+	"katydid-mp-account/pkg/field"
+	"maps"
+	"testing"
+)
+
+type extraKeysCase struct {
+	Extra field.KMap `json:"extra"`
+}
+
+func (c *extraKeysCase) ValidExtraKeys() ExtraKeyRules {
+	return ExtraKeyRules{
+		SceneAll:  ExtraKeyRule{Keys: []string{"known"}},
+		SceneBind: ExtraKeyRule{Policy: ExtraStrip, ReadOnly: []string{"locked"}},
+		SceneAdd:  ExtraKeyRule{Policy: ExtraReject},
+	}
+}
+
+func TestExtraKeysReadOnlyCheck(t *testing.T) {
+	src := field.KMap{"known": 1, "junk": 2}
+	tests := []struct {
+		scene Scene
+		tags  []string
+	}{
+		{SceneBind, nil},                         // 删除策略: 验证不上报也不删除
+		{SceneAdd, []string{string(TagUnknown)}}, // 拒绝策略
+		{SceneUpd, nil},                          // 默认允许
+	}
+	for _, tt := range tests {
+		obj := &extraKeysCase{Extra: maps.Clone(src)}
+		errs, _ := Check(obj, tt.scene)
+		var tags []string
+		for _, e := range errs {
+			tags = append(tags, e.Tag)
+		}
+		if len(tags) != len(tt.tags) || (len(tags) > 0 && tags[0] != tt.tags[0]) {
+			t.Errorf("%s: tags = %v, want %v", tt.scene, tags, tt.tags)
+		}
+		if !maps.Equal(obj.Extra, src) {
+			t.Errorf("%s: Check modified Extra: %v", tt.scene, obj.Extra)
+		}
+	}
+}
+
+func TestStripExtra(t *testing.T) {
+	obj := &extraKeysCase{Extra: field.KMap{"known": 1, "junk": 2, "locked": 3}}
+
+	stripped := StripExtra(obj, SceneBind)
+	if want := (field.KMap{"known": 1, "locked": 3}); !maps.Equal(stripped, want) {
+		t.Fatalf("StripExtra(bind) = %v, want %v", stripped, want)
+	}
+	if len(obj.Extra) != 3 {
+		t.Fatalf("StripExtra modified source: %v", obj.Extra)
+	}
+
+	// 非删除策略: 原样的副本
+	copied := StripExtra(obj, SceneUpd)
+	copied["new"] = 4
+	if len(copied) != 4 || len(obj.Extra) != 3 {
+		t.Fatalf("StripExtra(upd) = %v, source %v", copied, obj.Extra)
+	}
+	if StripExtra(&extraKeysCase{}, SceneBind) != nil {
+		t.Fatal("StripExtra of nil Extra should be nil")
+	}
+}
@@ -0,0 +1,22 @@
+package valid
+
+import (
+	"errors"
+	"katydid-mp-account/pkg/field"
+)
+
+// CheckKMapLimits 检查 KMap 载荷限制，超限时以 TagLimit 上报(param 为超限的键路径)，用于 IStructValidator
+// 值无法序列化时以 TagFormat 上报
+func CheckKMapLimits(m field.KMap, fieldName FieldName, limits field.KMapLimits, fn FuncReportError) bool {
+	err := m.CheckLimits(limits)
+	if err == nil {
+		return true
+	}
+	var limitErr *field.KMapLimitError
+	if errors.As(err, &limitErr) {
+		fn(m, fieldName, TagLimit, limitErr.Key)
+	} else {
+		fn(m, fieldName, TagFormat, "")
+	}
+	return false
+}
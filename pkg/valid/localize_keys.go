@@ -0,0 +1,147 @@
+package valid
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// 内置消息键(不在本地化规则中声明)
+const (
+	MsgValidationFailed = ErrCodeValidation           // 没有本地化规则的验证错误
+	MsgUnknownValidator = "unknown_validator_err"     // 未知的验证错误
+	MsgInvalidObject    = "invalid_object_validation" // 验证对象为空
+	MsgExtraUnknown     = "extra_unknown_err"         // 未声明的Extra键 {键}
+	MsgExtraReadOnly    = "extra_readonly_err"        // 只读的Extra键 {键}
+)
+
+// localizers 本地化规则注册表(用于消息目录完整性检查) reflect.Type -> ILocalizeValidator
+var localizers sync.Map
+
+// RegisterLocalizer 注册本地化规则类型(组合类型的规则会一起收集)，并检查规则格式
+func RegisterLocalizer(objs ...ILocalizeValidator) error {
+	var errs []error
+	for _, obj := range objs {
+		if e := checkLocalizer(reflect.TypeOf(obj), map[reflect.Type]bool{}); e != nil {
+			errs = append(errs, e)
+			continue
+		}
+		localizers.Store(reflect.TypeOf(obj), obj)
+	}
+	return errors.Join(errs...)
+}
+
+// checkLocalizer 递归检查类型及其组合类型的本地化规则
+func checkLocalizer(typ reflect.Type, visited map[reflect.Type]bool) error {
+	typ = indirectType(typ)
+	if typ == nil || typ.Kind() != reflect.Struct || visited[typ] {
+		return nil
+	}
+	visited[typ] = true
+
+	var errs []error
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous {
+			errs = append(errs, checkLocalizer(sf.Type, visited))
+		}
+	}
+	if rl, ok := ownImpl[ILocalizeValidator](reflect.New(typ).Interface(), "ValidLocalizeRules"); ok {
+		errs = append(errs, CheckLocalizeRules(typ, rl.ValidLocalizeRules()))
+	}
+	return errors.Join(errs...)
+}
+
+// LocalizeKeys 收集所有已注册类型(全部场景)的消息键和模板参数键 -> 声明的类型们
+func LocalizeKeys() map[string][]string {
+	keys := map[string][]string{}
+	add := func(key, source string) {
+		if key == "" {
+			return
+		}
+		for _, s := range keys[key] {
+			if s == source {
+				return
+			}
+		}
+		keys[key] = append(keys[key], source)
+	}
+	for _, key := range []string{
+		MsgValidationFailed, MsgUnknownValidator, MsgInvalidObject, MsgExtraUnknown, MsgExtraReadOnly,
+	} {
+		add(key, "valid")
+	}
+	for _, key := range builtinRuleKeys() {
+		add(key, "valid")
+	}
+
+	visited := map[reflect.Type]bool{}
+	localizers.Range(func(_, obj any) bool {
+		collectLocalizeKeys(reflect.TypeOf(obj), visited, add)
+		return true
+	})
+
+	for _, sources := range keys {
+		sort.Strings(sources)
+	}
+	return keys
+}
+
+// collectLocalizeKeys 递归收集类型及其组合类型的消息键
+func collectLocalizeKeys(typ reflect.Type, visited map[reflect.Type]bool, add func(key, source string)) {
+	typ = indirectType(typ)
+	if typ == nil || typ.Kind() != reflect.Struct || visited[typ] {
+		return
+	}
+	visited[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous {
+			collectLocalizeKeys(sf.Type, visited, add)
+		}
+	}
+
+	source := typ.String()
+
+	// 声明式规则自动生成的消息键
+	if rv, ok := ownImpl[IRuleValidator](reflect.New(typ).Interface(), "ValidRules"); ok {
+		for _, rules := range rv.ValidRules() {
+			for _, rule := range rules {
+				for _, key := range rule.msgKeys() {
+					add(key, source)
+				}
+			}
+		}
+	}
+
+	// 标签参数消息键
+	if pk, ok := ownImpl[ILocalizeParamKeys](reflect.New(typ).Interface(), "LocalizeParamKeys"); ok {
+		for _, key := range pk.LocalizeParamKeys() {
+			add(key, source)
+		}
+	}
+
+	rl, ok := ownImpl[ILocalizeValidator](reflect.New(typ).Interface(), "ValidLocalizeRules")
+	if !ok {
+		return
+	}
+	addParam := func(param LocalizeValidRuleParam) {
+		add(param.Msg, source)
+		// 字符串模板参数视为消息键(翻译时会先被翻译)
+		for _, p := range param.Template {
+			if key, ok := p.(string); ok {
+				add(key, source)
+			}
+		}
+	}
+	for _, rule := range rl.ValidLocalizeRules() {
+		for _, fieldRules := range rule.Rule1 {
+			for _, param := range fieldRules {
+				addParam(param)
+			}
+		}
+		for _, param := range rule.Rule2 {
+			addParam(param)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package valid
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"katydid-mp-account/pkg/i18n"
+	"reflect"
+)
+
+// msgErr 本地化规则 -> 错误信息
+func (p LocalizeValidRuleParam) msgErr(ee validator.FieldError) *MsgErr {
+	params := make([]any, 0, len(p.Template)+1)
+	for _, arg := range p.Template {
+		if key, ok := arg.(string); ok {
+			arg = i18n.Key(key) // 字符串模板参数为消息键
+		}
+		params = append(params, arg)
+	}
+	if p.WithParam {
+		if p.KeyParam {
+			params = append(params, i18n.Key(ee.Param()))
+		} else {
+			params = append(params, ee.Param())
+		}
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+	return &MsgErr{Msg: p.Msg, Params: params, Severity: p.Severity, fe: ee}
+}
+
+// check 检查单条本地化规则
+func (p LocalizeValidRuleParam) check() error {
+	if p.Msg == "" {
+		return errors.New("empty message key")
+	}
+	switch p.Severity {
+	case "", SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Errorf("unknown severity %q", p.Severity)
+	}
+	if p.KeyParam && !p.WithParam {
+		return errors.New("key param requires with param")
+	}
+	for i, arg := range p.Template {
+		switch arg.(type) {
+		case string, bool,
+			int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64,
+			float32, float64:
+		default:
+			return fmt.Errorf("template[%d] unsupported type %T", i, arg)
+		}
+	}
+	return nil
+}
+
+// CheckLocalizeRules 检查本地化规则(消息键/严重程度/模板参数/字段名)，注册时调用避免验证时才发现错误
+func CheckLocalizeRules(typ reflect.Type, sceneRules LocalizeValidRules) error {
+	typ = indirectType(typ)
+	var errs []error
+	for scene, rule := range sceneRules {
+		for tag, fieldRules := range rule.Rule1 {
+			for fieldName, param := range fieldRules {
+				if typ != nil && typ.Kind() == reflect.Struct {
+					if _, ok := typ.FieldByName(string(fieldName)); !ok {
+						errs = append(errs, fmt.Errorf("%s scene %d rule1 %s.%s: field not found",
+							typ, scene, tag, fieldName))
+						continue
+					}
+				}
+				if e := param.check(); e != nil {
+					errs = append(errs, fmt.Errorf("%s scene %d rule1 %s.%s: %w",
+						typ, scene, tag, fieldName, e))
+				}
+			}
+		}
+		for tag, param := range rule.Rule2 {
+			if e := param.check(); e != nil {
+				errs = append(errs, fmt.Errorf("%s scene %d rule2 %s: %w", typ, scene, tag, e))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LegacyLocalizeParam 旧格式 [3]any{msg, param(bool), template([]any)} -> LocalizeValidRuleParam (迁移用)
+func LegacyLocalizeParam(raw [3]any) (LocalizeValidRuleParam, error) {
+	var param LocalizeValidRuleParam
+	msg, ok := raw[0].(string)
+	if !ok {
+		return param, fmt.Errorf("legacy localize param[0] must be string, got %T", raw[0])
+	}
+	param.Msg = msg
+	if raw[1] != nil {
+		if param.WithParam, ok = raw[1].(bool); !ok {
+			return param, fmt.Errorf("legacy localize param[1] must be bool, got %T", raw[1])
+		}
+	}
+	if raw[2] != nil {
+		if param.Template, ok = raw[2].([]any); !ok {
+			return param, fmt.Errorf("legacy localize param[2] must be []any, got %T", raw[2])
+		}
+	}
+	return param, param.check()
+}
+
+// MustLegacyLocalizeParam 同 LegacyLocalizeParam，格式错误时 panic (用于规则声明)
+func MustLegacyLocalizeParam(raw [3]any) LocalizeValidRuleParam {
+	param, e := LegacyLocalizeParam(raw)
+	if e != nil {
+		panic(e)
+	}
+	return param
+}
@@ -0,0 +1,262 @@
+package valid
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+)
+
+// ExtraField Extra字段名，额外验证错误以 Extra.<key> 上报
+const ExtraField = "Extra"
+
+// ExtraJSONField Extra字段json名，额外验证错误的路径为 extra.<key>
+const ExtraJSONField = "extra"
+
+// redactedValue 敏感值脱敏
+const redactedValue = "******"
+
+// MsgErr 定义错误信息结构体
+type MsgErr struct {
+	Err      error    `json:"-"`
+	Msg      string   `json:"msg"`              // 消息键
+	Params   []any    `json:"params,omitempty"` // 消息参数
+	Text     string   `json:"text,omitempty"`   // 本地化文本 (Localize后填充)
+	Field    string   `json:"field,omitempty"`  // json路径 (如 name, tags[3], extra.websiteUrl)
+	Tag      string   `json:"tag,omitempty"`    // 验证失败的标签
+	Value    any      `json:"value,omitempty"`  // 被拒绝的值 (敏感字段脱敏)
+	Scene    Scene    `json:"scene"`            // 验证场景
+	Severity Severity `json:"severity"`         // 严重程度
+
+	fe validator.FieldError // 来源验证错误
+}
+
+// Severity 验证结果严重程度
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // 错误(阻止操作)
+	SeverityWarning Severity = "warning" // 警告(不阻止操作)
+	SeverityInfo    Severity = "info"    // 提示
+)
+
+// ErrEnvelope 标准错误响应体(验证错误和应用错误 errs.Error 共用)
+type ErrEnvelope struct {
+	Code     string    `json:"code"`
+	Msg      string    `json:"msg"`
+	Params   []any     `json:"params,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	Errors   []*MsgErr `json:"errors,omitempty"`
+	Warnings []*MsgErr `json:"warnings,omitempty"` // 不阻止操作的警告/提示
+}
+
+// Translator 消息翻译器 (如 i18n.Localizer)
+type Translator interface {
+	Translate(key string, params ...any) string
+}
+
+// ErrCodeValidation 验证失败错误码
+const ErrCodeValidation = "validation_failed"
+
+// NewErrEnvelope 验证错误 -> 标准错误响应体
+func NewErrEnvelope(msgErrs []*MsgErr) *ErrEnvelope {
+	return &ErrEnvelope{
+		Code:   ErrCodeValidation,
+		Msg:    MsgValidationFailed,
+		Errors: msgErrs,
+	}
+}
+
+// WithWarnings 附带警告/提示
+func (e *ErrEnvelope) WithWarnings(warns []*MsgErr) *ErrEnvelope {
+	e.Warnings = warns
+	return e
+}
+
+// Blocking 是否阻止操作(严重程度为错误)
+func (e *MsgErr) Blocking() bool {
+	return blocking(e.Severity)
+}
+
+// splitSeverity 按严重程度拆分为 错误 + 警告/提示
+func splitSeverity(msgErrs []*MsgErr) (errs, warns []*MsgErr) {
+	for _, msgErr := range msgErrs {
+		if msgErr.Blocking() {
+			errs = append(errs, msgErr)
+		} else {
+			warns = append(warns, msgErr)
+		}
+	}
+	return errs, warns
+}
+
+// Localize 翻译错误消息
+func (e *MsgErr) Localize(t Translator) string {
+	e.Text = t.Translate(e.Msg, e.Params...)
+	return e.Text
+}
+
+// Localize 翻译响应体及全部错误消息
+func (e *ErrEnvelope) Localize(t Translator) *ErrEnvelope {
+	e.Text = t.Translate(e.Msg, e.Params...)
+	LocalizeErrs(e.Errors, t)
+	LocalizeErrs(e.Warnings, t)
+	return e
+}
+
+// LocalizeErrs 翻译全部错误消息
+func LocalizeErrs(msgErrs []*MsgErr, t Translator) []*MsgErr {
+	for _, msgErr := range msgErrs {
+		msgErr.Localize(t)
+	}
+	return msgErrs
+}
+
+// fill 补充字段路径/标签/值/场景
+func (e *MsgErr) fill(obj any, scene Scene, rule *ViewRule) {
+	e.Scene = scene
+	if e.Severity == "" {
+		e.Severity = SeverityError
+	}
+	if e.fe == nil {
+		return
+	}
+	e.Field = jsonPath(reflect.TypeOf(obj), e.fe.StructNamespace())
+	e.Tag = e.fe.Tag()
+	e.Value = e.fe.Value()
+	if cause := errors.Unwrap(e.fe); cause != nil && e.Err == nil {
+		e.Err = cause // 上下文验证失败原因(依赖错误/超时)
+	}
+
+	// 内部可见的字段/Extra键视为敏感值
+	if key, ok := strings.CutPrefix(e.fe.StructField(), ExtraField+"."); ok {
+		if rule.extraAudience(key) >= AudienceInternal {
+			e.Value = redactedValue
+		}
+	} else if rule.Fields[FieldName(e.fe.StructField())] >= AudienceInternal {
+		e.Value = redactedValue
+	}
+}
+
+// reportExtraError 上报额外验证错误
+func reportExtraError(sl validator.StructLevel, value any, key, tag, param string) {
+	sl.ReportError(value, ExtraJSONField+"."+key, ExtraField+"."+key, tag, param)
+}
+
+// sensitiveRules 获取响应视图规则，用于错误值脱敏
+func sensitiveRules(obj any) *ViewRule {
+	rule := &ViewRule{Fields: map[FieldName]Audience{}, Extra: map[string]Audience{}}
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return rule
+		}
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Struct {
+		collectViewRules(val, SceneRes, rule)
+	}
+	return rule
+}
+
+// jsonFieldName 字段名 -> json名称
+func jsonFieldName(typ reflect.Type, fieldName FieldName) string {
+	typ = indirectType(typ)
+	if typ.Kind() == reflect.Struct {
+		if sf, ok := typ.FieldByName(string(fieldName)); ok {
+			if name, _, skip := parseJSONTag(sf); !skip && name != "" {
+				return name
+			}
+		}
+	}
+	return string(fieldName)
+}
+
+// jsonPath 结构体命名空间 -> json路径 (Organization.Base.Tags[3] -> tags[3])
+func jsonPath(rootTyp reflect.Type, structNs string) string {
+	return resolveNs(rootTyp, structNs).path
+}
+
+// nsField 结构体命名空间解析结果
+type nsField struct {
+	path string       // json路径
+	top  reflect.Type // 字段所在的非组合结构体(本地化规则归属)
+	decl reflect.Type // 声明字段的类型(组合类型提升的字段归属于声明它的类型)
+	name FieldName    // 字段名(不含下标)
+}
+
+// resolveNs 解析结构体命名空间，组合类型平铺，保留下标
+// 手动展开的切片元素以元素类型名开头 (Organization.Addresses[0].Address.Street)，类型名段跳过
+func resolveNs(rootTyp reflect.Type, structNs string) nsField {
+	parts := strings.Split(structNs, ".")
+	if len(parts) <= 1 {
+		return nsField{path: structNs}
+	}
+
+	typ := indirectType(rootTyp)
+	res := nsField{top: typ}
+	afterIndex := false
+	var sb strings.Builder
+	for i, part := range parts[1:] {
+		name, suffix := part, ""
+		if j := strings.IndexByte(part, '['); j >= 0 {
+			name, suffix = part[:j], part[j:]
+		}
+		last := i == len(parts)-2
+
+		if afterIndex && typ != nil && typ.Kind() == reflect.Struct && name == typ.Name() && suffix == "" {
+			if _, ok := typ.FieldByName(name); !ok {
+				afterIndex = false
+				continue
+			}
+		}
+		afterIndex = false
+
+		seg := name
+		if typ != nil && typ.Kind() == reflect.Struct {
+			if sf, ok := typ.FieldByName(name); ok {
+				jsonName, _, _ := parseJSONTag(sf)
+				if sf.Anonymous && jsonName == "" {
+					seg = "" // 组合类型平铺
+				} else if jsonName != "" {
+					seg = jsonName
+				}
+				if last {
+					res.decl = typ
+					for _, idx := range sf.Index[:len(sf.Index)-1] {
+						res.decl = indirectType(res.decl.Field(idx).Type)
+					}
+					res.name = FieldName(name)
+				}
+				typ = indirectType(sf.Type)
+				for n := strings.Count(suffix, "["); n > 0 && typ != nil; n-- {
+					switch typ.Kind() {
+					case reflect.Slice, reflect.Array, reflect.Map:
+						typ = indirectType(typ.Elem())
+					default:
+						typ = nil
+					}
+				}
+				if !last && !sf.Anonymous && typ != nil && typ.Kind() == reflect.Struct {
+					res.top = typ // 进入嵌套结构体
+				}
+				afterIndex = suffix != ""
+			} else {
+				typ = nil
+			}
+		} else {
+			typ = nil
+		}
+
+		if seg == "" && suffix == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(seg)
+		sb.WriteString(suffix)
+	}
+	res.path = sb.String()
+	return res
+}
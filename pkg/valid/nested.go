@@ -0,0 +1,196 @@
+package valid
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+)
+
+// diveField 需要手动展开验证的切片/数组/map字段(未声明 dive 标签，元素为需要验证的结构体)
+type diveField struct {
+	index    []int
+	name     string // 字段名
+	jsonName string // json名
+}
+
+// nestedPlan 嵌套结构体验证计划
+type nestedPlan struct {
+	types []reflect.Type               // 需要注册的嵌套结构体类型(不含组合类型)
+	dives map[reflect.Type][]diveField // 结构体类型 -> 需要手动展开的字段
+}
+
+// newNestedPlan 从根类型递归收集嵌套结构体(命名字段/指针/切片/数组/map元素)
+func newNestedPlan(rootTyp reflect.Type) *nestedPlan {
+	plan := &nestedPlan{dives: map[reflect.Type][]diveField{}}
+	plan.collect(indirectType(rootTyp), map[reflect.Type]bool{}, true)
+	return plan
+}
+
+func (p *nestedPlan) collect(typ reflect.Type, visited map[reflect.Type]bool, root bool) {
+	if visited[typ] {
+		return
+	}
+	visited[typ] = true
+	if !root {
+		p.types = append(p.types, typ)
+	}
+	p.collectFields(typ, typ, nil, visited)
+}
+
+// collectFields 遍历字段(组合类型的字段归属于外层结构体)
+func (p *nestedPlan) collectFields(owner, typ reflect.Type, index []int, visited map[reflect.Type]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldTyp := indirectType(sf.Type)
+
+		if sf.Anonymous {
+			if fieldTyp.Kind() == reflect.Struct {
+				p.collectFields(owner, fieldTyp, fieldIndex, visited)
+			}
+			continue
+		}
+		if !sf.IsExported() || sf.Tag.Get("validate") == "-" {
+			continue
+		}
+
+		switch fieldTyp.Kind() {
+		case reflect.Struct:
+			if validatable(fieldTyp, map[reflect.Type]bool{}) {
+				p.collect(fieldTyp, visited, false)
+			}
+		case reflect.Slice, reflect.Array, reflect.Map:
+			elemTyp := indirectType(fieldTyp.Elem())
+			if elemTyp.Kind() != reflect.Struct || !validatable(elemTyp, map[reflect.Type]bool{}) {
+				continue
+			}
+			p.collect(elemTyp, visited, false)
+			if !hasDive(sf) {
+				jsonName, _, _ := parseJSONTag(sf)
+				if jsonName == "" {
+					jsonName = sf.Name
+				}
+				p.dives[owner] = append(p.dives[owner], diveField{index: fieldIndex, name: sf.Name, jsonName: jsonName})
+			}
+		}
+	}
+}
+
+// validatable 结构体(含组合/嵌套类型)是否声明了验证规则或验证标签
+func validatable(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[typ] {
+		return false
+	}
+	visited[typ] = true
+
+	ptr := reflect.PointerTo(typ)
+	for _, iface := range []reflect.Type{
+		reflect.TypeFor[IFieldValidator](), reflect.TypeFor[IExtraValidator](),
+		reflect.TypeFor[IStructValidator](), reflect.TypeFor[IRuleValidator](),
+	} {
+		if ptr.Implements(iface) {
+			return true
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			return true
+		}
+		fieldTyp := indirectType(sf.Type)
+		switch fieldTyp.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			fieldTyp = indirectType(fieldTyp.Elem())
+		}
+		if fieldTyp.Kind() == reflect.Struct && validatable(fieldTyp, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDive 字段是否声明了 dive 标签(由 validator 自行展开)
+func hasDive(sf reflect.StructField) bool {
+	for _, tag := range strings.Split(sf.Tag.Get("validate"), ",") {
+		if tag == "dive" {
+			return true
+		}
+	}
+	return false
+}
+
+// validDives 手动展开验证切片/数组/map元素，错误以 字段[下标]. 为前缀上报
+func (p *nestedPlan) validDives(validate *validator.Validate, sl validator.StructLevel) {
+	cur := sl.Current()
+	for _, dive := range p.dives[cur.Type()] {
+		fieldVal, err := cur.FieldByIndexErr(dive.index)
+		if err != nil {
+			continue // 组合类型指针为空
+		}
+		switch fieldVal.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < fieldVal.Len(); i++ {
+				p.validElem(validate, sl, dive, fmt.Sprintf("[%d]", i), fieldVal.Index(i))
+			}
+		case reflect.Map:
+			iter := fieldVal.MapRange()
+			for iter.Next() {
+				p.validElem(validate, sl, dive, fmt.Sprintf("[%v]", iter.Key().Interface()), iter.Value())
+			}
+		}
+	}
+}
+
+func (p *nestedPlan) validElem(
+	validate *validator.Validate, sl validator.StructLevel,
+	dive diveField, index string, elem reflect.Value,
+) {
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			return
+		}
+		elem = elem.Elem()
+	}
+	var errs validator.ValidationErrors
+	if e := validate.Struct(addrOf(elem).Interface()); errors.As(e, &errs) && len(errs) > 0 {
+		sl.ReportValidationErrors(dive.jsonName+index+".", dive.name+index+".", errs)
+	}
+}
+
+// addrOf 获取可寻址的指针(不可寻址时拷贝，如map元素)
+func addrOf(val reflect.Value) reflect.Value {
+	if val.CanAddr() {
+		return val.Addr()
+	}
+	ptr := reflect.New(val.Type())
+	ptr.Elem().Set(val)
+	return ptr
+}
+
+// ownerErrs 同一结构体的验证错误
+type ownerErrs struct {
+	typ  reflect.Type
+	errs validator.ValidationErrors
+}
+
+// groupByOwner 按字段所在结构体分组(保持顺序)
+func groupByOwner(rootTyp reflect.Type, validateErrs validator.ValidationErrors) []*ownerErrs {
+	var groups []*ownerErrs
+	index := map[reflect.Type]*ownerErrs{}
+	for _, ee := range validateErrs {
+		typ := resolveNs(rootTyp, ee.StructNamespace()).top
+		if typ == nil {
+			typ = rootTyp
+		}
+		group, ok := index[typ]
+		if !ok {
+			group = &ownerErrs{typ: typ}
+			index[typ] = group
+			groups = append(groups, group)
+		}
+		group.errs = append(group.errs, ee)
+	}
+	return groups
+}
@@ -0,0 +1,136 @@
+package valid
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 字段依赖(部分验证时，结构体规则上报的字段依赖其他字段)
+type (
+	IFieldDepValidator interface {
+		ValidFieldDeps() FieldDeps
+	}
+
+	// FieldDeps 上报的字段 -> 依赖的字段，依赖的字段有变更时同样验证
+	FieldDeps = map[FieldName][]FieldName
+)
+
+// CheckFields 部分验证(如 PATCH 请求)，只返回 fields(json路径，如 display、extra.adminNote)相关的错误
+//
+//   - 字段自身及其子路径(tags 包含 tags[1]，addresses 包含 addresses[0].zip)
+//   - 父路径的错误(extra.adminNote 包含 extra 的大小限制)
+//   - 依赖 fields 的结构体规则(见 IFieldDepValidator)
+func CheckFields(obj any, scene Scene, fields ...string) (errs, warns []*MsgErr) {
+	if obj == nil || len(fields) == 0 {
+		return Check(obj, scene)
+	}
+
+	v := Get()
+	validate, e := v.plan(obj, scene)
+	if e != nil {
+		return []*MsgErr{{Err: e}}, nil
+	}
+
+	// -- 执行验证(有缓存)，只保留相关字段的错误 --
+	var validateErrs validator.ValidationErrors
+	if e = validate.Struct(obj); e == nil {
+		return nil, nil
+	}
+	if !errors.As(e, &validateErrs) {
+		return splitSeverity(v.handleValidationError(obj, scene, e))
+	}
+	validateErrs = filterFields(reflect.TypeOf(obj), validateErrs, fields)
+	if len(validateErrs) == 0 {
+		return nil, nil
+	}
+	return splitSeverity(v.handleValidationError(obj, scene, validateErrs))
+}
+
+// FieldsFromJSON 请求体中出现的字段(json路径)，extra 对象展开为 extra.<key>
+func FieldsFromJSON(body []byte) ([]string, error) {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(data))
+	for key, raw := range data {
+		var extra map[string]json.RawMessage
+		if key == ExtraJSONField && json.Unmarshal(raw, &extra) == nil && extra != nil {
+			for k := range extra {
+				fields = append(fields, ExtraJSONField+"."+k)
+			}
+			continue
+		}
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// filterFields 过滤出与 fields 相关的验证错误
+func filterFields(objTyp reflect.Type, validateErrs validator.ValidationErrors, fields []string) validator.ValidationErrors {
+	rootTyp := indirectType(objTyp)
+	deps := map[string][]string{}
+	collectFieldDeps(rootTyp, deps, map[reflect.Type]bool{})
+
+	var filtered validator.ValidationErrors
+	for _, ee := range validateErrs {
+		path := jsonPath(rootTyp, ee.StructNamespace())
+		if matchFields(path, fields) {
+			filtered = append(filtered, ee)
+			continue
+		}
+		for _, dep := range deps[path] {
+			if matchFields(dep, fields) {
+				filtered = append(filtered, ee)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// matchFields 错误路径是否与任一字段相关(相同/子路径/父路径)
+func matchFields(path string, fields []string) bool {
+	for _, f := range fields {
+		if path == f || isSubPath(path, f) || isSubPath(f, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubPath path 是否为 parent 的子路径 (parent.x / parent[i])
+func isSubPath(path, parent string) bool {
+	rest, ok := strings.CutPrefix(path, parent)
+	return ok && rest != "" && (rest[0] == '.' || rest[0] == '[')
+}
+
+// collectFieldDeps 收集字段依赖(含组合类型)，字段名转为json名称
+func collectFieldDeps(typ reflect.Type, deps map[string][]string, visited map[reflect.Type]bool) {
+	if typ.Kind() != reflect.Struct || visited[typ] {
+		return
+	}
+	visited[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous {
+			collectFieldDeps(indirectType(sf.Type), deps, visited)
+		}
+	}
+
+	dv, ok := ownImpl[IFieldDepValidator](reflect.New(typ).Interface(), "ValidFieldDeps")
+	if !ok {
+		return
+	}
+	for name, depNames := range dv.ValidFieldDeps() {
+		path := jsonFieldName(typ, name)
+		for _, dep := range depNames {
+			deps[path] = append(deps[path], jsonFieldName(typ, dep))
+		}
+	}
+}
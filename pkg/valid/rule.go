@@ -0,0 +1,412 @@
+package valid
+
+import (
+	"fmt"
+	"katydid-mp-account/pkg/i18n"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 声明式规则标签(结构体验证时上报)
+const (
+	TagRunes   Tag = "runes"   // 字符数 (param: min,max)
+	TagCharset Tag = "charset" // 字符集 (param: 字符集名称)
+	TagEnum    Tag = "enum"    // 枚举 (param: 可选值)
+	TagURL     Tag = "url"     // 网址
+	TagEmail   Tag = "email"   // 邮箱
+	TagPhone   Tag = "phone"   // 电话
+	TagRegexp  Tag = "regexp"  // 正则 (param: 表达式)
+	TagLen     Tag = "len"     // 切片长度 (param: min,max)
+)
+
+// 声明式规则(字段 -> 规则)
+type (
+	IRuleValidator interface {
+		ValidRules() RuleValidRules
+	}
+
+	RuleValidRules = map[Scene]RuleValidRule
+	RuleValidRule  = map[FieldName]*Rule
+)
+
+// CharClass 字符集
+type CharClass uint16
+
+const (
+	CharLetter     CharClass = 1 << iota // 字母(含中文等)
+	CharNumber                           // 数字
+	CharUnderscore                       // _
+	CharHyphen                           // -
+	CharSpace                            // 空格
+	CharDot                              // .
+
+	Alnum = CharLetter | CharNumber                               // 字母+数字
+	Word  = CharLetter | CharNumber | CharUnderscore | CharHyphen // 字母+数字+_+-
+)
+
+var charClassNames = []struct {
+	class CharClass
+	name  string
+}{
+	{CharLetter, "letter"}, {CharNumber, "number"}, {CharUnderscore, "underscore"},
+	{CharHyphen, "hyphen"}, {CharSpace, "space"}, {CharDot, "dot"},
+}
+
+// String 字符集名称 (本地化键为 charset_<名称>)
+func (c CharClass) String() string {
+	switch c {
+	case Word:
+		return "word"
+	case Alnum:
+		return "alnum"
+	}
+	var names []string
+	for _, n := range charClassNames {
+		if c&n.class != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, "_")
+}
+
+// pattern 字符集正则 (JSON Schema 导出)
+func (c CharClass) pattern() string {
+	var sb strings.Builder
+	for _, n := range []struct {
+		class CharClass
+		expr  string
+	}{
+		{CharLetter, `\p{L}`}, {CharNumber, `\p{N}`}, {CharUnderscore, `_`},
+		{CharHyphen, `\-`}, {CharSpace, ` `}, {CharDot, `.`},
+	} {
+		if c&n.class != 0 {
+			sb.WriteString(n.expr)
+		}
+	}
+	return "^[" + sb.String() + "]*$"
+}
+
+// match 字符是否属于字符集
+func (c CharClass) match(r rune) bool {
+	return (c&CharLetter != 0 && unicode.IsLetter(r)) ||
+		(c&CharNumber != 0 && unicode.IsNumber(r)) ||
+		(c&CharUnderscore != 0 && r == '_') ||
+		(c&CharHyphen != 0 && r == '-') ||
+		(c&CharSpace != 0 && r == ' ') ||
+		(c&CharDot != 0 && r == '.')
+}
+
+// Rule 声明式验证规则，链式组合 valid.Runes(1, 50).Charset(valid.Word).Label("org_name")
+type Rule struct {
+	checks   []ruleCheck
+	each     *Rule    // 切片元素规则
+	label    string   // 字段显示名(消息键)
+	msg      string   // 自定义消息键(默认 rule_<tag>_err)
+	optional bool     // 零值跳过
+	severity Severity // 严重程度(默认错误)
+}
+
+// ruleCheck 单项检查
+type ruleCheck struct {
+	tag    Tag
+	param  string
+	args   []any // 本地化参数(跟在 label 后)
+	fn     func(value reflect.Value) bool
+	schema func(s *JSONSchema) // 导出 JSON Schema 关键字
+}
+
+// Runes 字符数 [min, max]
+func Runes(min, max int) *Rule { return new(Rule).Runes(min, max) }
+
+// Charset 字符集
+func Charset(class CharClass) *Rule { return new(Rule).Charset(class) }
+
+// Enum 枚举
+func Enum(values ...any) *Rule { return new(Rule).Enum(values...) }
+
+// URL 网址(http/https)
+func URL() *Rule { return new(Rule).URL() }
+
+// Email 邮箱
+func Email() *Rule { return new(Rule).Email() }
+
+// Phone 电话
+func Phone() *Rule { return new(Rule).Phone() }
+
+// Regexp 正则
+func Regexp(pattern string) *Rule { return new(Rule).Regexp(pattern) }
+
+// Slice 切片长度 [min, max]
+func Slice(min, max int) *Rule { return new(Rule).Slice(min, max) }
+
+// Runes 字符数 [min, max]
+func (r *Rule) Runes(min, max int) *Rule {
+	return r.add(TagRunes, fmt.Sprintf("%d,%d", min, max), []any{min, max}, func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		n := utf8.RuneCountInString(value.String())
+		return n >= min && n <= max
+	}).describe(func(s *JSONSchema) {
+		s.typed("string")
+		s.MinLength, s.MaxLength = &min, &max
+	})
+}
+
+// Charset 字符集
+func (r *Rule) Charset(class CharClass) *Rule {
+	return r.add(TagCharset, class.String(), []any{i18n.Key("charset_" + class.String())}, func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		for _, c := range value.String() {
+			if !class.match(c) {
+				return false
+			}
+		}
+		return true
+	}).describe(func(s *JSONSchema) {
+		s.addPattern(class.pattern())
+	})
+}
+
+// Enum 枚举
+func (r *Rule) Enum(values ...any) *Rule {
+	options := make([]string, len(values))
+	for i, v := range values {
+		options[i] = fmt.Sprint(v)
+	}
+	return r.add(TagEnum, strings.Join(options, " "), []any{strings.Join(options, "/")}, func(value reflect.Value) bool {
+		v := fmt.Sprint(value.Interface())
+		for _, option := range options {
+			if v == option {
+				return true
+			}
+		}
+		return false
+	}).describe(func(s *JSONSchema) {
+		s.Enum = values
+	})
+}
+
+// URL 网址(http/https)
+func (r *Rule) URL() *Rule {
+	return r.add(TagURL, "", nil, func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		u, err := url.Parse(value.String())
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	}).describe(func(s *JSONSchema) {
+		s.typed("string")
+		s.Format = "uri"
+	})
+}
+
+// Email 邮箱
+func (r *Rule) Email() *Rule {
+	return r.add(TagEmail, "", nil, func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		addr, err := mail.ParseAddress(value.String())
+		return err == nil && addr.Address == value.String()
+	}).describe(func(s *JSONSchema) {
+		s.typed("string")
+		s.Format = "email"
+	})
+}
+
+var phoneRegexp = regexp.MustCompile(`^\+?[1-9][0-9]{4,14}$`)
+
+// Phone 电话(国际格式，允许空格和-分隔)
+func (r *Rule) Phone() *Rule {
+	return r.add(TagPhone, "", nil, func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		return phoneRegexp.MatchString(strings.NewReplacer(" ", "", "-", "").Replace(value.String()))
+	}).describe(func(s *JSONSchema) {
+		s.addPattern(`^\+?[1-9][0-9 \-]{4,}$`) // 宽松格式，服务端去除分隔符后再检查位数
+	})
+}
+
+// Regexp 正则(声明时编译，表达式错误直接 panic)
+func (r *Rule) Regexp(pattern string) *Rule {
+	re := regexp.MustCompile(pattern)
+	return r.add(TagRegexp, pattern, nil, func(value reflect.Value) bool {
+		return value.Kind() == reflect.String && re.MatchString(value.String())
+	}).describe(func(s *JSONSchema) {
+		s.addPattern(pattern)
+	})
+}
+
+// Slice 切片长度 [min, max]
+func (r *Rule) Slice(min, max int) *Rule {
+	return r.add(TagLen, fmt.Sprintf("%d,%d", min, max), []any{min, max}, func(value reflect.Value) bool {
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return false
+		}
+		return value.Len() >= min && value.Len() <= max
+	}).describe(func(s *JSONSchema) {
+		s.typed("array")
+		s.MinItems, s.MaxItems = &min, &max
+	})
+}
+
+// Each 切片元素规则
+func (r *Rule) Each(elem *Rule) *Rule {
+	r.each = elem
+	return r
+}
+
+// Label 字段显示名(消息键)，作为第一个本地化参数
+func (r *Rule) Label(label string) *Rule {
+	r.label = label
+	return r
+}
+
+// Msg 自定义消息键(覆盖 rule_<tag>_err)
+func (r *Rule) Msg(msg string) *Rule {
+	r.msg = msg
+	return r
+}
+
+// Optional 零值时跳过验证
+func (r *Rule) Optional() *Rule {
+	r.optional = true
+	return r
+}
+
+// Level 严重程度，警告/提示不阻止操作
+func (r *Rule) Level(severity Severity) *Rule {
+	r.severity = severity
+	return r
+}
+
+// Warn 验证失败时只警告
+func (r *Rule) Warn() *Rule { return r.Level(SeverityWarning) }
+
+// Valid 验证值是否符合规则
+func (r *Rule) Valid(value any) bool {
+	ok := true
+	r.check(reflect.ValueOf(value), "", func(any, string, *ruleCheck) { ok = false })
+	return ok
+}
+
+func (r *Rule) add(tag Tag, param string, args []any, fn func(value reflect.Value) bool) *Rule {
+	r.checks = append(r.checks, ruleCheck{tag: tag, param: param, args: args, fn: fn})
+	return r
+}
+
+// describe 设置最后一项检查的 JSON Schema 关键字
+func (r *Rule) describe(schema func(s *JSONSchema)) *Rule {
+	r.checks[len(r.checks)-1].schema = schema
+	return r
+}
+
+// check 执行检查，失败时回调(值, 元素下标后缀, 检查项)
+func (r *Rule) check(value reflect.Value, index string, report func(value any, index string, c *ruleCheck)) {
+	value = indirectValue(value)
+	if r.optional && (!value.IsValid() || value.IsZero()) {
+		return
+	}
+	for i := range r.checks {
+		c := &r.checks[i]
+		if !value.IsValid() || !c.fn(value) {
+			report(valueInterface(value), index, c)
+			return // 同一字段只报第一个错误
+		}
+	}
+	if r.each != nil && (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) {
+		for i := 0; i < value.Len(); i++ {
+			r.each.check(value.Index(i), index+"["+strconv.Itoa(i)+"]", report)
+		}
+	}
+}
+
+// find 按标签+参数查找检查项(含元素规则)
+func (r *Rule) find(tag, param string) *ruleCheck {
+	for i := range r.checks {
+		if string(r.checks[i].tag) == tag && r.checks[i].param == param {
+			return &r.checks[i]
+		}
+	}
+	if r.each != nil {
+		return r.each.find(tag, param)
+	}
+	return nil
+}
+
+// msgKey 检查项的消息键
+func (r *Rule) msgKey(c *ruleCheck) string {
+	if r.msg != "" {
+		return r.msg
+	}
+	return "rule_" + string(c.tag) + "_err"
+}
+
+// builtinRuleKeys 内置检查项的默认消息键和命名字符集的消息键(任意规则都可能用到，用于消息目录完整性检查)
+func builtinRuleKeys() []string {
+	keys := make([]string, 0, 10)
+	for _, tag := range []Tag{TagRunes, TagCharset, TagEnum, TagURL, TagEmail, TagPhone, TagRegexp, TagLen} {
+		keys = append(keys, new(Rule).msgKey(&ruleCheck{tag: tag}))
+	}
+	for _, class := range []CharClass{Word, Alnum} {
+		keys = append(keys, "charset_"+class.String())
+	}
+	return keys
+}
+
+// msgKeys 规则用到的全部消息键(用于消息目录完整性检查)
+func (r *Rule) msgKeys() []string {
+	var keys []string
+	if r.label != "" {
+		keys = append(keys, r.label)
+	}
+	for rule := r; rule != nil; rule = rule.each {
+		for i := range rule.checks {
+			keys = append(keys, r.msgKey(&rule.checks[i]))
+			for _, arg := range rule.checks[i].args {
+				if key, ok := arg.(i18n.Key); ok {
+					keys = append(keys, string(key))
+				}
+			}
+		}
+	}
+	return keys
+}
+
+func indirectValue(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func valueInterface(value reflect.Value) any {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// sortedFieldNames 字段名排序(错误顺序稳定)
+func sortedFieldNames(rules RuleValidRule) []FieldName {
+	names := make([]FieldName, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
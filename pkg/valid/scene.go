@@ -0,0 +1,206 @@
+package valid
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Scene 验证场景，每个场景占一位，继承关系记录在场景注册表中
+//
+// 匹配规则(字段/额外/声明式/上下文/结构体/本地化/视图规则一致):
+//   - 规则场景为 SceneAll 时总是适用
+//   - 规则场景可以是多个场景的组合(如 SceneSave = SceneAdd|SceneUpd 表示添加或更新)
+//   - 规则适用于验证场景的每一位: 规则场景包含该位，或包含该位的任一祖先场景
+//   - 多个规则场景同时适用时，按 全局 -> 祖先场景 -> 当前场景 的顺序合并，后者覆盖前者
+type Scene uint64
+
+const (
+	SceneAll Scene = 0 // 所有的场景
+
+	SceneBind Scene = 1 << 0 // 请求数据绑定
+	SceneAdd  Scene = 1 << 1 // 添加/新增
+	SceneDel  Scene = 1 << 2 // 删除/移除
+	SceneUpd  Scene = 1 << 3 // 更新/修改
+	SceneGet  Scene = 1 << 4 // 获取/查询
+	SceneRes  Scene = 1 << 5 // 返回/响应
+
+	SceneSave Scene = SceneAdd | SceneUpd // 保存(添加或更新)，仅用于声明规则
+)
+
+// sceneRegistry 场景注册表
+type sceneRegistry struct {
+	mu      sync.RWMutex
+	names   map[Scene]string // 场景位 -> 名称
+	byName  map[string]Scene // 名称 -> 场景位
+	parents map[Scene]Scene  // 场景位 -> 父场景(可多个)
+	used    Scene            // 已分配的位
+}
+
+var scenes = func() *sceneRegistry {
+	r := &sceneRegistry{
+		names:   map[Scene]string{},
+		byName:  map[string]Scene{},
+		parents: map[Scene]Scene{},
+	}
+	for _, s := range []struct {
+		scene Scene
+		name  string
+	}{
+		{SceneBind, "bind"}, {SceneAdd, "add"}, {SceneDel, "del"},
+		{SceneUpd, "upd"}, {SceneGet, "get"}, {SceneRes, "res"},
+	} {
+		r.names[s.scene] = s.name
+		r.byName[s.name] = s.scene
+		r.used |= s.scene
+	}
+	return r
+}()
+
+// RegisterScene 注册自定义场景(分配未使用的位)，parents 为继承的父场景，父场景的规则同样适用
+func RegisterScene(name string, parents ...Scene) (Scene, error) {
+	scenes.mu.Lock()
+	defer scenes.mu.Unlock()
+
+	if name == "" {
+		return 0, fmt.Errorf("scene name cannot be empty")
+	}
+	if _, ok := scenes.byName[name]; ok {
+		return 0, fmt.Errorf("scene %q already registered", name)
+	}
+	var parent Scene
+	for _, p := range parents {
+		if p == SceneAll || p&^scenes.used != 0 {
+			return 0, fmt.Errorf("scene %q parent %#x not registered", name, uint64(p))
+		}
+		parent |= p
+	}
+	if scenes.used == ^Scene(0) {
+		return 0, fmt.Errorf("scene %q: no free scene bit", name)
+	}
+
+	scene := Scene(1) << bits.TrailingZeros64(uint64(^scenes.used))
+	scenes.names[scene] = name
+	scenes.byName[name] = scene
+	scenes.parents[scene] = parent
+	scenes.used |= scene
+	return scene, nil
+}
+
+// MustRegisterScene 同 RegisterScene，失败时 panic (用于包级变量声明)
+func MustRegisterScene(name string, parents ...Scene) Scene {
+	scene, err := RegisterScene(name, parents...)
+	if err != nil {
+		panic(err)
+	}
+	return scene
+}
+
+// String 场景名称，组合场景以 | 连接
+func (s Scene) String() string {
+	if s == SceneAll {
+		return "all"
+	}
+	scenes.mu.RLock()
+	defer scenes.mu.RUnlock()
+
+	var names []string
+	for _, bit := range s.bits() {
+		if name, ok := scenes.names[bit]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("scene(%#x)", uint64(bit)))
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// Lineage 场景及其所有祖先场景(祖先在前)
+func (s Scene) Lineage() []Scene {
+	scenes.mu.RLock()
+	defer scenes.mu.RUnlock()
+
+	var lineage []Scene
+	seen := Scene(0)
+	var visit func(scene Scene)
+	visit = func(scene Scene) {
+		for _, bit := range scene.bits() {
+			if seen&bit != 0 {
+				continue
+			}
+			seen |= bit
+			visit(scenes.parents[bit])
+			lineage = append(lineage, bit)
+		}
+	}
+	visit(s)
+	return lineage
+}
+
+// Match 规则场景(s)是否适用于验证场景
+func (s Scene) Match(scene Scene) bool {
+	if s == SceneAll {
+		return true
+	}
+	scenes.mu.RLock()
+	defer scenes.mu.RUnlock()
+	for _, bit := range scene.bits() {
+		if s&scenes.expand(bit) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// expand 场景位及其所有祖先场景位
+func (r *sceneRegistry) expand(bit Scene) Scene {
+	expanded := bit
+	queue := []Scene{bit}
+	for len(queue) > 0 {
+		for _, parent := range r.parents[queue[0]].bits() {
+			if expanded&parent == 0 {
+				expanded |= parent
+				queue = append(queue, parent)
+			}
+		}
+		queue = queue[1:]
+	}
+	return expanded
+}
+
+// bits 拆分为单个场景位
+func (s Scene) bits() []Scene {
+	var list []Scene
+	for v := uint64(s); v != 0; v &= v - 1 {
+		list = append(list, Scene(1)<<bits.TrailingZeros64(v))
+	}
+	return list
+}
+
+// matchScenes 适用于验证场景的规则场景，按 全局 -> 祖先场景 -> 当前场景 排序(后者覆盖前者)
+func matchScenes[T any](sceneRules map[Scene]T, scene Scene) []Scene {
+	keys := make([]Scene, 0, len(sceneRules))
+	for key := range sceneRules {
+		if key.Match(scene) {
+			keys = append(keys, key)
+		}
+	}
+	rank := func(key Scene) int {
+		switch {
+		case key == SceneAll:
+			return 0
+		case key&scene == scene:
+			return 2 // 直接声明当前场景
+		}
+		return 1 // 通过祖先场景继承
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := rank(keys[i]), rank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
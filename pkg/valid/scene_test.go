@@ -0,0 +1,122 @@
+package valid
+
+import (
+	"katydid-mp-account/pkg/field"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// 测试场景: upd -> child -> grand
+var (
+	testSceneChild = MustRegisterScene("test.child", SceneUpd)
+	testSceneGrand = MustRegisterScene("test.grand", testSceneChild)
+)
+
+func TestSceneLineage(t *testing.T) {
+	tests := []struct {
+		scene Scene
+		want  []Scene
+	}{
+		{SceneAll, nil},
+		{SceneAdd, []Scene{SceneAdd}},
+		{SceneSave, []Scene{SceneAdd, SceneUpd}},
+		{testSceneChild, []Scene{SceneUpd, testSceneChild}},
+		{testSceneGrand, []Scene{SceneUpd, testSceneChild, testSceneGrand}},
+	}
+	for _, tt := range tests {
+		if got := tt.scene.Lineage(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s.Lineage() = %v, want %v", tt.scene, got, tt.want)
+		}
+	}
+}
+
+func TestSceneMatch(t *testing.T) {
+	tests := []struct {
+		rule, scene Scene
+		want        bool
+	}{
+		{SceneAll, SceneGet, true},
+		{SceneAdd, SceneAdd, true},
+		{SceneAdd, SceneUpd, false},
+		{SceneSave, SceneAdd, true},
+		{SceneSave, SceneUpd, true},
+		{SceneSave, SceneGet, false},
+		{SceneUpd, testSceneGrand, true},        // 祖先场景的规则适用
+		{testSceneChild, testSceneGrand, true},  // 父场景的规则适用
+		{testSceneGrand, testSceneChild, false}, // 子场景的规则不适用于父场景
+		{testSceneChild, SceneUpd, false},       // 自定义场景的规则不影响父场景
+		{SceneAdd, SceneAdd | SceneUpd, false},  // 组合验证场景的每一位都需匹配
+		{SceneSave, SceneAdd | testSceneChild, true},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Match(tt.scene); got != tt.want {
+			t.Errorf("%s.Match(%s) = %v, want %v", tt.rule, tt.scene, got, tt.want)
+		}
+	}
+}
+
+// sceneRulesCase 每个场景声明一个总是失败的规则，通过失败的标签判断生效的规则
+type sceneRulesCase struct {
+	All    string     `validate:"t-all"`
+	Add    string     `validate:"t-add"`
+	Upd    string     `validate:"t-upd"`
+	Child  string     `validate:"t-child"`
+	Grand  string     `validate:"t-grand"`
+	Shared string     `validate:"t-shared"`
+	Extra  field.KMap `json:"extra"`
+}
+
+func (c *sceneRulesCase) ValidFieldRules() FieldValidRules {
+	fail := func(reflect.Value, string) bool { return false }
+	pass := func(reflect.Value, string) bool { return true }
+	return FieldValidRules{
+		SceneAll:       FieldValidRule{"t-all": fail},
+		SceneAdd:       FieldValidRule{"t-add": fail},
+		SceneUpd:       FieldValidRule{"t-upd": fail, "t-shared": fail},
+		testSceneChild: FieldValidRule{"t-child": fail, "t-shared": pass}, // 覆盖父场景的规则
+		testSceneGrand: FieldValidRule{"t-grand": fail},
+	}
+}
+
+func (c *sceneRulesCase) ValidExtraRules() (field.KMap, ExtraValidRules) {
+	fail := func(any) bool { return false }
+	return c.Extra, ExtraValidRules{
+		SceneAdd:       ExtraValidRule{"t-extra-add": {Field: "k", ValidFn: fail}},
+		testSceneChild: ExtraValidRule{"t-extra-child": {Field: "k", ValidFn: fail}},
+	}
+}
+
+// ValidStructRules 按 全局 -> 祖先场景 -> 当前场景 逐个调用
+func (c *sceneRulesCase) ValidStructRules(scene Scene, fn FuncReportError) {
+	if scene == SceneGet {
+		fn(c.All, "All", "t-struct-get", "")
+	}
+}
+
+func TestSceneRulesIsolated(t *testing.T) {
+	tests := []struct {
+		scene Scene
+		want  []string
+	}{
+		{SceneBind, []string{"t-all"}},
+		{SceneGet, []string{"t-all", "t-struct-get"}},
+		{SceneAdd, []string{"t-add", "t-all", "t-extra-add"}},
+		{SceneUpd, []string{"t-all", "t-shared", "t-upd"}},
+		{testSceneChild, []string{"t-all", "t-child", "t-extra-child", "t-upd"}},
+		{testSceneGrand, []string{"t-all", "t-child", "t-extra-child", "t-grand", "t-upd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scene.String(), func(t *testing.T) {
+			errs, _ := Check(&sceneRulesCase{Extra: field.KMap{"k": "v"}}, tt.scene)
+			var tags []string
+			for _, e := range errs {
+				tags = append(tags, e.Tag)
+			}
+			slices.Sort(tags)
+			if !slices.Equal(tags, tt.want) {
+				t.Fatalf("failed tags = %v, want %v", tags, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package valid
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDraft 导出的 JSON Schema 版本
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema 验证规则导出(类型+场景)，供前端生成表单/接口文档
+// 无法导出的规则(自定义函数)以 x-rules 列出标签，x-label 为字段显示名(消息键)
+type JSONSchema struct {
+	Schema     string                 `json:"$schema,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+	Type       string                 `json:"type,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *JSONSchema            `json:"items,omitempty"`
+	Enum       []any                  `json:"enum,omitempty"`
+	MinLength  *int                   `json:"minLength,omitempty"`
+	MaxLength  *int                   `json:"maxLength,omitempty"`
+	MinItems   *int                   `json:"minItems,omitempty"`
+	MaxItems   *int                   `json:"maxItems,omitempty"`
+	Minimum    *float64               `json:"minimum,omitempty"`
+	Maximum    *float64               `json:"maximum,omitempty"`
+	Pattern    string                 `json:"pattern,omitempty"`
+	AllOf      []*JSONSchema          `json:"allOf,omitempty"`
+	ReadOnly   bool                   `json:"readOnly,omitempty"`
+	Additional *bool                  `json:"additionalProperties,omitempty"`
+	Label      string                 `json:"x-label,omitempty"`
+	Rules      []string               `json:"x-rules,omitempty"`
+}
+
+// Schema 导出类型在场景下的有效验证规则
+// 来源: 字段类型(json标签)、validate标签、声明式规则(ValidRules)、额外规则(ValidExtraRules)
+func Schema(obj any, scene Scene) *JSONSchema {
+	typ := indirectType(reflect.TypeOf(obj))
+	schema := Get().structSchema(typ, scene, map[reflect.Type]bool{})
+	schema.Schema = JSONSchemaDraft
+	schema.Title = typ.Name()
+	return schema
+}
+
+// SchemaJSON 导出 JSON Schema 文本
+func SchemaJSON(obj any, scene Scene) ([]byte, error) {
+	return json.MarshalIndent(Schema(obj, scene), "", "  ")
+}
+
+// structSchema 结构体 -> object(组合类型平铺，嵌套结构体使用自身的规则)
+func (v *Validator) structSchema(typ reflect.Type, scene Scene, visiting map[reflect.Type]bool) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+	if visiting[typ] {
+		return schema // 递归类型
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	v.fieldsSchema(typ, scene, schema, visiting)
+	extraKeysSchema(typ, scene, schema)
+	return schema
+}
+
+// extraKeysSchema Extra键策略 -> 已声明的键/只读键/是否允许未声明的键
+func extraKeysSchema(typ reflect.Type, scene Scene, schema *JSONSchema) {
+	plan := newExtraKeyPlan(typ, scene)
+	extra := schema.Properties[ExtraJSONField]
+	if plan == nil || extra == nil {
+		return
+	}
+	if extra.Properties == nil {
+		extra.Properties = map[string]*JSONSchema{}
+	}
+	for key := range plan.declared {
+		if extra.Properties[key] == nil {
+			extra.Properties[key] = &JSONSchema{}
+		}
+		extra.Properties[key].ReadOnly = plan.readOnly[key]
+	}
+	if plan.policy == ExtraReject {
+		additional := false
+		extra.Additional = &additional
+	}
+}
+
+// fieldsSchema 字段类型+validate标签，再合并声明式/额外规则(组合类型先处理，外层覆盖)
+func (v *Validator) fieldsSchema(typ reflect.Type, scene Scene, schema *JSONSchema, visiting map[reflect.Type]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		name, _, skip := parseJSONTag(sf)
+		if skip || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+		if sf.Anonymous && name == "" && indirectType(sf.Type).Kind() == reflect.Struct {
+			v.fieldsSchema(indirectType(sf.Type), scene, schema, visiting)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		prop := v.typeSchema(sf.Type, scene, visiting)
+		if tagSchema(sf.Tag.Get("validate"), prop) {
+			schema.addRequired(name)
+		}
+		schema.Properties[name] = prop
+	}
+
+	obj := reflect.New(typ).Interface()
+	for fieldName, rule := range v.sceneRules(obj, scene) {
+		if !blocking(rule.severity) {
+			continue
+		}
+		if prop := schema.Properties[jsonFieldName(typ, fieldName)]; prop != nil {
+			rule.schema(prop)
+		}
+	}
+	if ev, ok := ownImpl[IExtraValidator](obj, "ValidExtraRules"); ok {
+		v.extraSchema(ev, scene, schema)
+	}
+}
+
+// extraSchema 额外规则 -> extra.properties
+func (v *Validator) extraSchema(ev IExtraValidator, scene Scene, schema *JSONSchema) {
+	_, sceneRules := ev.ValidExtraRules()
+	if sceneRules == nil {
+		return
+	}
+	extra := schema.Properties[ExtraJSONField]
+	if extra == nil {
+		extra = &JSONSchema{Type: "object"}
+		schema.Properties[ExtraJSONField] = extra
+	}
+	if extra.Properties == nil {
+		extra.Properties = map[string]*JSONSchema{}
+	}
+	for _, s := range matchScenes(sceneRules, scene) {
+		tags := make([]string, 0, len(sceneRules[s]))
+		for tag, info := range sceneRules[s] {
+			if !blocking(info.severity()) {
+				continue // 警告/提示不阻止提交，不作为约束导出
+			}
+			tags = append(tags, string(tag))
+		}
+		sort.Strings(tags) // 同一键有多条规则时输出稳定
+		for _, t := range tags {
+			tag, info := Tag(t), sceneRules[s][Tag(t)]
+			key := info.Field
+			if key == "" {
+				key = string(tag)
+			}
+			prop := extra.Properties[key]
+			if prop == nil {
+				prop = &JSONSchema{}
+				extra.Properties[key] = prop
+			}
+			if tag == TagRequired {
+				extra.addRequired(key)
+			}
+			switch {
+			case info.ValidFn != nil:
+				prop.Rules = append(prop.Rules, string(tag))
+			case info.Rule != nil:
+				info.Rule.schema(prop)
+			}
+		}
+	}
+}
+
+// blocking 严重程度是否阻止操作(默认错误)
+func blocking(severity Severity) bool {
+	return severity != SeverityWarning && severity != SeverityInfo
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// typeSchema 字段类型 -> JSON Schema 类型
+func (v *Validator) typeSchema(typ reflect.Type, scene Scene, visiting map[reflect.Type]bool) *JSONSchema {
+	typ = indirectType(typ)
+	if typ == timeType {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+	if marshaler := reflect.TypeFor[json.Marshaler](); typ.Implements(marshaler) || reflect.PointerTo(typ).Implements(marshaler) {
+		return &JSONSchema{} // 自定义序列化，类型未知
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Format: "byte"}
+		}
+		return &JSONSchema{Type: "array", Items: v.typeSchema(typ.Elem(), scene, visiting)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object"}
+	case reflect.Struct:
+		return v.structSchema(typ, scene, visiting)
+	}
+	return &JSONSchema{}
+}
+
+// tagSchema validate标签 -> 关键字，返回是否必填
+func tagSchema(tag string, schema *JSONSchema) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+	required := false
+	target := schema
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(part, "=")
+		switch name {
+		case "", "omitempty":
+		case "dive":
+			if target.Items == nil {
+				return required // 非切片，无法描述元素
+			}
+			target = target.Items
+		case string(TagRequired):
+			if target == schema {
+				required = true
+			}
+		case "min", "max", "len":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				target.Rules = append(target.Rules, part)
+				continue
+			}
+			target.bound(name, n)
+		case "oneof":
+			for _, option := range strings.Fields(param) {
+				target.Enum = append(target.Enum, option)
+			}
+		case "email":
+			target.Format = "email"
+		case "url", "uri":
+			target.Format = "uri"
+		default:
+			target.Rules = append(target.Rules, name) // 自定义规则(函数)
+		}
+	}
+	return required
+}
+
+// bound min/max/len -> 对应类型的长度/数量/数值范围
+func (s *JSONSchema) bound(name string, n int) {
+	lo, hi := name == "min" || name == "len", name == "max" || name == "len"
+	switch s.Type {
+	case "string":
+		if lo {
+			s.MinLength = &n
+		}
+		if hi {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lo {
+			s.MinItems = &n
+		}
+		if hi {
+			s.MaxItems = &n
+		}
+	default:
+		f := float64(n)
+		if lo {
+			s.Minimum = &f
+		}
+		if hi {
+			s.Maximum = &f
+		}
+	}
+}
+
+// typed 设置类型(字段类型未知时，如 Extra 键)
+func (s *JSONSchema) typed(typ string) {
+	if s.Type == "" {
+		s.Type = typ
+	}
+}
+
+// addRequired 添加必填字段(去重排序)
+func (s *JSONSchema) addRequired(name string) {
+	for _, r := range s.Required {
+		if r == name {
+			return
+		}
+	}
+	s.Required = append(s.Required, name)
+	sort.Strings(s.Required)
+}
+
+// addPattern 添加正则(已有正则时以 allOf 组合)
+func (s *JSONSchema) addPattern(pattern string) {
+	if s.Pattern == "" {
+		s.Pattern = pattern
+		return
+	}
+	s.AllOf = append(s.AllOf, &JSONSchema{Pattern: pattern})
+}
+
+// schema 声明式规则 -> 关键字(元素规则 -> items)
+func (r *Rule) schema(s *JSONSchema) {
+	if r.label != "" {
+		s.Label = r.label
+	}
+	for i := range r.checks {
+		if c := &r.checks[i]; c.schema != nil {
+			c.schema(s)
+		} else {
+			s.Rules = append(s.Rules, string(c.tag))
+		}
+	}
+	if r.each != nil {
+		if s.Items == nil {
+			s.Items = &JSONSchema{}
+		}
+		r.each.schema(s.Items)
+	}
+}
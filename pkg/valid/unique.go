@@ -0,0 +1,173 @@
+package valid
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// TagUnique 唯一性标签
+const TagUnique Tag = "unique"
+
+// UniqueScope 唯一性范围
+type UniqueScope uint8
+
+const (
+	UniqueGlobal UniqueScope = 0 // 全局唯一
+	UniqueParent UniqueScope = 1 // 同父级唯一
+	UniqueOwner  UniqueScope = 2 // 同所属唯一
+)
+
+// String 范围名称(作为标签参数)
+func (s UniqueScope) String() string {
+	switch s {
+	case UniqueParent:
+		return "parent"
+	case UniqueOwner:
+		return "owner"
+	}
+	return "global"
+}
+
+// 唯一性验证
+type (
+	// IUniqueChecker 唯一性查询(由仓库实现，通过 Provide 注入)
+	IUniqueChecker interface {
+		Exists(ctx context.Context, query UniqueQuery) (bool, error)
+	}
+
+	// UniqueQuery 唯一性查询条件，字段名由仓库映射为列
+	UniqueQuery struct {
+		Entity     string      // 实体名
+		Field      FieldName   // 唯一字段
+		Value      any         // 唯一值
+		Scope      UniqueScope // 范围
+		ScopeField FieldName   // 范围字段 (父级/所属)
+		ScopeValue any         // 范围值
+		ExcludeID  any         // 排除的ID (更新时排除自身，nil/零值不排除)
+	}
+)
+
+// Unique 唯一性上下文验证规则，值为零值时跳过
+func Unique(query UniqueQuery) ContextValidRuleInfo {
+	return ContextValidRuleInfo{
+		Field: query.Field,
+		Param: query.Scope.String(),
+		ValidFn: func(ctx context.Context) (bool, error) {
+			if query.Value == nil || reflect.ValueOf(query.Value).IsZero() {
+				return true, nil
+			}
+			checker, err := Dep[IUniqueChecker](ctx)
+			if err != nil {
+				return false, err
+			}
+			exists, err := checker.Exists(ctx, query)
+			return !exists, err
+		},
+	}
+}
+
+// IConstraintError 提供违反的约束名的数据库错误(驱动错误的适配)
+type IConstraintError interface {
+	ConstraintName() string
+}
+
+var (
+	pgConstraintRegexp    = regexp.MustCompile(`constraint "([^"]+)"`)           // PostgreSQL: violates unique constraint "uk_name"
+	mysqlConstraintRegexp = regexp.MustCompile(`for key '(?:[^'.]+\.)?([^']+)'`) // MySQL: Duplicate entry 'x' for key 'table.uk_name'
+)
+
+// ConstraintName 从数据库错误中提取违反的约束名(不依赖驱动)
+// 依次尝试: 错误链中的 IConstraintError / ConstraintName 字段(如 pgconn.PgError) -> PostgreSQL/MySQL 错误信息
+func ConstraintName(err error) (string, bool) {
+	var name string
+	walkErrs(err, func(e error) bool {
+		if ce, ok := e.(IConstraintError); ok {
+			name = ce.ConstraintName()
+		} else if val := reflect.Indirect(reflect.ValueOf(e)); val.Kind() == reflect.Struct {
+			if f := val.FieldByName("ConstraintName"); f.IsValid() && f.Kind() == reflect.String {
+				name = f.String()
+			}
+		}
+		return name == ""
+	})
+	if name != "" {
+		return name, true
+	}
+
+	text := err.Error()
+	for _, re := range []*regexp.Regexp{pgConstraintRegexp, mysqlConstraintRegexp} {
+		if m := re.FindStringSubmatch(text); m != nil {
+			return m[1], true
+		}
+	}
+	return "", false
+}
+
+// walkErrs 遍历错误链(含 errors.Join)，fn 返回 false 时停止
+func walkErrs(err error, fn func(e error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				if !walkErrs(inner, fn) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// constraintInfo 数据库约束对应的字段+标签
+type constraintInfo struct {
+	field FieldName
+	tag   Tag
+	param string
+}
+
+var constraints sync.Map // 约束名 -> constraintInfo
+
+// RegisterConstraint 注册数据库约束，约束冲突时映射为字段验证错误(与验证时的错误一致)
+func RegisterConstraint(name string, fieldName FieldName, tag Tag, param string) {
+	constraints.Store(name, constraintInfo{field: fieldName, tag: tag, param: param})
+}
+
+// CheckConstraint 数据库错误 -> 验证错误，违反的约束名(见 ConstraintName)与已注册的约束名相同时返回 true
+func CheckConstraint(obj any, scene Scene, err error) ([]*MsgErr, bool) {
+	if err == nil || obj == nil {
+		return nil, false
+	}
+	name, ok := ConstraintName(err)
+	if !ok {
+		return nil, false
+	}
+	value, ok := constraints.Load(name)
+	if !ok {
+		return nil, false
+	}
+	info := value.(constraintInfo)
+
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, false
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, false
+	}
+	fe := newFieldError(val, info.field, info.tag, info.param, err)
+	return Get().handleValidationError(obj, scene, validator.ValidationErrors{fe}), true
+}
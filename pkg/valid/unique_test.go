@@ -0,0 +1,71 @@
+package valid
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// pgError 同 pgconn.PgError 的约束名字段
+type pgError struct {
+	Code           string
+	ConstraintName string
+}
+
+func (e *pgError) Error() string { return "ERROR: duplicate key value (SQLSTATE " + e.Code + ")" }
+
+type adapterError struct{ name string }
+
+func (e adapterError) Error() string          { return "constraint violated" }
+func (e adapterError) ConstraintName() string { return e.name }
+
+func TestConstraintName(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+		ok   bool
+	}{
+		{"driver field", &pgError{Code: "23505", ConstraintName: "uk_name"}, "uk_name", true},
+		{"wrapped driver field", fmt.Errorf("insert: %w", &pgError{ConstraintName: "uk_name_owner"}), "uk_name_owner", true},
+		{"joined adapter", errors.Join(errors.New("tx"), adapterError{"uk_name"}), "uk_name", true},
+		{"postgres text", errors.New(`ERROR: duplicate key value violates unique constraint "uk_name_owner" (SQLSTATE 23505)`), "uk_name_owner", true},
+		{"mysql 8 text", errors.New(`Error 1062 (23000): Duplicate entry '1-a' for key 'organization.uk_name'`), "uk_name", true},
+		{"mysql 5.7 text", errors.New(`Error 1062: Duplicate entry 'a' for key 'uk_name_owner'`), "uk_name_owner", true},
+		{"unknown", errors.New("connection refused"), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ConstraintName(tt.err)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: ConstraintName = %q,%v, want %q,%v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+type constraintCase struct {
+	Name string `json:"name"`
+}
+
+func TestCheckConstraintExact(t *testing.T) {
+	RegisterConstraint("uk_test_name", "Name", TagUnique, "")
+
+	tests := []struct {
+		name string
+		err  error
+		ok   bool
+	}{
+		{"exact", &pgError{ConstraintName: "uk_test_name"}, true},
+		{"longer name with same prefix", &pgError{ConstraintName: "uk_test_name_owner"}, false},
+		{"text with same prefix", errors.New(`violates unique constraint "uk_test_name_owner"`), false},
+	}
+	for _, tt := range tests {
+		msgErrs, ok := CheckConstraint(&constraintCase{Name: "a"}, SceneAdd, tt.err)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && (len(msgErrs) != 1 || msgErrs[0].Field != "name" || msgErrs[0].Tag != string(TagUnique)) {
+			t.Errorf("%s: msgErrs = %v", tt.name, msgErrs)
+		}
+	}
+}
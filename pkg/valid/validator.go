@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"katydid-mp-account/pkg/field"
+	"katydid-mp-account/pkg/i18n"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
 )
@@ -16,26 +19,18 @@ var (
 
 // Validator 验证器
 type Validator struct {
-	validate *validator.Validate
-	regTypes *sync.Map // 验证注册类型缓存
-	regLocs  *sync.Map // 本地化文本缓存
+	rules    FieldValidRule // 全局字段验证规则
+	rulesMu  sync.RWMutex
+	regTypes *sync.Map // 验证注册缓存 (类型+场景) -> *validator.Validate
+	regLocs  *sync.Map // 本地化文本缓存 (类型+场景) -> LocalizeValidRule
+	regRules *sync.Map // 声明式规则缓存 (类型+场景) -> RuleValidRule
 }
 
-// Scene 验证场景
-type Scene uint64
-
-const (
-	SceneAll Scene = 0 // 所有的场景
-
-	SceneBind Scene = 1 << 0 // 请求数据绑定
-	SceneAdd  Scene = 1 << 1 // 添加/新增
-	SceneDel  Scene = 1 << 2 // 删除/移除
-	SceneUpd  Scene = 1 << 3 // 更新/修改
-	SceneGet  Scene = 1 << 4 // 获取/查询
-	SceneRes  Scene = 1 << 5 // 返回/响应
-
-	SceneCustom Scene = SceneRes // 自定义 custom << ?
-)
+// planKey 验证缓存键，同一类型在不同场景下的规则不同
+type planKey struct {
+	typ   reflect.Type
+	scene Scene
+}
 
 // Tag 字段标签
 type Tag string
@@ -45,6 +40,7 @@ const (
 	TagFormat   Tag = "format"
 	TagRange    Tag = "range"
 	TagCheck    Tag = "check"
+	TagLimit    Tag = "limit"
 )
 
 // FieldName 字段名称
@@ -64,15 +60,17 @@ type (
 // 额外(Extra)字段验证
 type (
 	IExtraValidator interface {
-		ValidExtraRules() (map[string]any, ExtraValidRules)
+		ValidExtraRules() (field.KMap, ExtraValidRules)
 	}
 
 	ExtraValidRules    = map[Scene]ExtraValidRule
 	ExtraValidRule     = map[Tag]ExtraValidRuleInfo
 	ExtraValidRuleInfo struct {
-		Field   string
-		Param   string
-		ValidFn func(value any) bool
+		Field    string
+		Param    string
+		ValidFn  func(value any) bool
+		Rule     *Rule    // 声明式规则(ValidFn为空时使用)
+		Severity Severity // 严重程度(默认使用 Rule 声明的，否则为错误)
 	}
 )
 
@@ -91,111 +89,191 @@ type (
 	ILocalizeValidator interface {
 		ValidLocalizeRules() LocalizeValidRules
 	}
+	// ILocalizeParamKeys 标签参数为消息键(KeyParam)时声明可能的参数(用于消息目录完整性检查)
+	ILocalizeParamKeys interface {
+		LocalizeParamKeys() []string
+	}
 
 	LocalizeValidRules = map[Scene]LocalizeValidRule
 	LocalizeValidRule  struct {
 		Rule1 map[Tag]map[FieldName]LocalizeValidRuleParam
 		Rule2 map[Tag]LocalizeValidRuleParam
 	}
-	LocalizeValidRuleParam = [3]any // {msg, param, template([]any)}
+	LocalizeValidRuleParam struct {
+		Msg       string   // 消息键
+		WithParam bool     // 是否追加验证标签参数 (如 max=10 的 10)
+		KeyParam  bool     // 标签参数为消息键(翻译后填充，需 WithParam)
+		Template  []any    // 模板参数 (字符串参数为消息键，翻译后填充)
+		Severity  Severity // 严重程度，默认错误
+	}
 )
 
-// MsgErr 定义错误信息结构体
-type MsgErr struct {
-	Err    error
-	Msg    string
-	Params []any
-}
-
 func Get() *Validator {
 	vOnce.Do(func() {
-		opts := []validator.Option{
-			validator.WithRequiredStructEnabled(),
-		}
-
 		valid = &Validator{
-			validate: validator.New(opts...),
+			rules:    FieldValidRule{},
 			regTypes: &sync.Map{},
 			regLocs:  &sync.Map{},
+			regRules: &sync.Map{},
 		}
-
-		// 设置Tag <- 默认json标签
-		//validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		//	name := fld.Tag.Get("json")
-		//	if name == "-" {
-		//		return fld.Name
-		//	}
-		//	return name
-		//})
 	})
 	return valid
 }
 
-// RegisterFieldRule 注册字段验证规则
+// newValidate 创建验证实例(每个类型+场景独立一份，避免规则互相覆盖)
+func (v *Validator) newValidate() (*validator.Validate, error) {
+	opts := []validator.Option{
+		validator.WithRequiredStructEnabled(),
+	}
+	validate := validator.New(opts...)
+
+	// 设置Tag <- 默认json标签
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name, _, skip := parseJSONTag(fld)
+		if skip || name == "" {
+			return fld.Name
+		}
+		return name
+	})
+
+	// 注册全局字段验证规则
+	for tag, rule := range v.globalRules() {
+		if e := validate.RegisterValidation(string(tag), func(fl validator.FieldLevel) bool {
+			return rule(fl.Field(), fl.Param())
+		}); e != nil {
+			return nil, e
+		}
+	}
+	return validate, nil
+}
+
+// globalRules 全局字段验证规则快照
+func (v *Validator) globalRules() FieldValidRule {
+	v.rulesMu.RLock()
+	defer v.rulesMu.RUnlock()
+	rules := make(FieldValidRule, len(v.rules))
+	for tag, rule := range v.rules {
+		rules[tag] = rule
+	}
+	return rules
+}
+
+// RegisterFieldRule 注册全局字段验证规则(所有类型+场景共用)
 func RegisterFieldRule(fieldRules FieldValidRule) {
 	v := Get()
-	// 注册字段验证规则
+	v.rulesMu.Lock()
 	for tag, rule := range fieldRules {
-		_ = v.validate.RegisterValidation(string(tag), func(fl validator.FieldLevel) bool {
-			return rule(fl.Field(), fl.Param())
-		})
+		v.rules[tag] = rule
 	}
+	v.rulesMu.Unlock()
+
+	// 已缓存的验证实例不含新规则，清除后重新注册
+	v.regTypes.Clear()
 }
 
 // Check 根据场景执行验证，并返回本地化错误信息
-func Check(obj any, scene Scene) []*MsgErr {
+// errs 为阻止操作的错误，warns 为不阻止操作的警告/提示(严重程度见 Severity)
+func Check(obj any, scene Scene) (errs, warns []*MsgErr) {
 	if obj == nil {
 		return []*MsgErr{{
 			Err: errors.New("validation object cannot be nil"),
-			Msg: "invalid_object_validation",
-		}}
+			Msg: MsgInvalidObject,
+		}}, nil
 	}
 
 	v := Get()
-	typ := reflect.TypeOf(obj)
-
-	// -- 注册验证(设置缓存) --
-	if _, ok := v.regTypes.Load(typ); !ok {
-		if e := v.registerValidations(obj, scene); e != nil {
-			return []*MsgErr{{Err: e}}
-		}
-		v.regTypes.Store(typ, true)
+	validate, e := v.plan(obj, scene)
+	if e != nil {
+		return []*MsgErr{{Err: e}}, nil
 	}
 
 	// -- 执行验证(有缓存) --
-	if e := v.validate.Struct(obj); e != nil {
-		return v.handleValidationError(obj, scene, e)
+	if e = validate.Struct(obj); e != nil {
+		return splitSeverity(v.handleValidationError(obj, scene, e))
 	}
-	return nil
+	return nil, nil
 }
 
-// registerValidations 注册验证规则
-func (v *Validator) registerValidations(obj any, scene Scene) error {
-	// -- 字段验证注册 --
-	if e := v.validFields(obj, scene); e != nil {
-		return e
+// plan 获取当前类型+场景的验证实例(没有就注册并缓存)
+func (v *Validator) plan(obj any, scene Scene) (*validator.Validate, error) {
+	key := planKey{typ: reflect.TypeOf(obj), scene: scene}
+	validate, ok := v.regTypes.Load(key)
+	if !ok {
+		vv, e := v.registerValidations(obj, scene)
+		if e != nil {
+			return nil, e
+		}
+		validate, _ = v.regTypes.LoadOrStore(key, vv)
 	}
+	return validate.(*validator.Validate), nil
+}
 
-	v.validate.RegisterStructValidation(func(sl validator.StructLevel) {
-		cObj := sl.Current().Addr().Interface()
+// registerValidations 创建当前类型+场景的验证实例并注册验证规则
+func (v *Validator) registerValidations(obj any, scene Scene) (*validator.Validate, error) {
+	validate, e := v.newValidate()
+	if e != nil {
+		return nil, e
+	}
 
-		// -- 额外验证注册 --
-		v.validExtra(cObj, sl, scene)
+	// -- 字段验证注册(含嵌套结构体) --
+	nested := newNestedPlan(reflect.TypeOf(obj))
+	visited := map[reflect.Type]bool{}
+	for _, typ := range append([]reflect.Type{reflect.TypeOf(obj)}, nested.types...) {
+		if e = checkOwnMethods(typ, visited); e != nil {
+			return nil, e
+		}
+	}
+	tagRules := make(map[Tag]typeRules)
+	if e = v.validFields(obj, scene, tagRules); e != nil {
+		return nil, e
+	}
+	for _, typ := range nested.types {
+		if e = v.validFields(reflect.New(typ).Interface(), scene, tagRules); e != nil {
+			return nil, e
+		}
+	}
+	rootTyp := indirectType(reflect.TypeOf(obj))
+	globals := v.globalRules()
+	for tag, rules := range tagRules {
+		globalRule := globals[tag]
+		if e = validate.RegisterValidation(string(tag), func(fl validator.FieldLevel) bool {
+			rule, ok := rules.lookup(fl.Parent(), rootTyp)
+			if !ok {
+				rule = globalRule
+			}
+			if rule == nil {
+				return true // 当前场景无规则，直接通过
+			}
+			return rule(fl.Field(), fl.Param())
+		}); e != nil {
+			return nil, e
+		}
+	}
 
-		// -- 结构验证注册 --
-		v.validStruct(cObj, sl, scene)
-	}, obj)
-	return nil
+	// -- 结构体级验证计划(额外/Extra键/声明式/结构体规则) --
+	structTypes := []any{obj}
+	plans := map[reflect.Type]*compiledPlan{rootTyp: v.compilePlan(rootTyp, scene)}
+	for _, typ := range nested.types {
+		structTypes = append(structTypes, reflect.New(typ).Interface())
+		plans[typ] = v.compilePlan(typ, scene)
+	}
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		plans[sl.Current().Type()].valid(sl)
+
+		// -- 未声明dive的切片元素展开验证 --
+		nested.validDives(validate, sl)
+	}, structTypes...)
+	return validate, nil
 }
 
-// validFields 注册字段验证规则
-func (v *Validator) validFields(obj any, scene Scene) error {
+// validFields 按声明类型收集字段验证规则，其他场景的规则以nil占位(标签必须注册，否则验证时panic)
+func (v *Validator) validFields(obj any, scene Scene, tagRules map[Tag]typeRules) error {
 	// 处理嵌入字段的验证规则
-	if e := v.processEmbeddedValidations(obj, scene, 1, nil); e != nil {
+	if e := v.processEmbeddedValidations(obj, scene, tagRules); e != nil {
 		return e
 	}
 
-	fv, ok := obj.(IFieldValidator)
+	fv, ok := ownImpl[IFieldValidator](obj, "ValidFieldRules")
 	if !ok {
 		return nil
 	}
@@ -206,116 +284,213 @@ func (v *Validator) validFields(obj any, scene Scene) error {
 		return nil
 	}
 
-	// 筛选出当前场景的验证规则
-	scenes := make([]Scene, 0)
-	for key := range sceneRules {
-		if key == SceneAll {
-			scenes = append(scenes, SceneAll) // 添加全局场景
-		} else if (key & scene) == scene {
-			scenes = append(scenes, key) // 添加当前场景(必须是全部包含)
+	// 筛选出当前场景的验证规则(全局 -> 祖先场景 -> 当前场景)
+	scenes := matchScenes(sceneRules, scene)
+
+	// 其他场景的验证规则占位
+	typ := indirectType(reflect.TypeOf(obj))
+	for _, tRules := range sceneRules {
+		for tag := range tRules {
+			if tagRules[tag] == nil {
+				tagRules[tag] = make(typeRules)
+			}
+			if _, ok := tagRules[tag][typ]; !ok {
+				tagRules[tag][typ] = nil
+			}
 		}
 	}
 
 	// 遍历所有场景的验证规则
-	tagRules := make(map[Tag]FieldValidRuleFn)
 	for _, s := range scenes {
 		if tRules := sceneRules[s]; tRules != nil {
 			for tag, rule := range tRules {
-				tagRules[tag] = rule // 合并验证规则
+				tagRules[tag][typ] = rule // 合并验证规则
 			}
 		}
 	}
+	return nil
+}
 
-	// 注册验证规则
-	for tag, rule := range tagRules {
-		if e := v.validate.RegisterValidation(string(tag), func(fl validator.FieldLevel) bool {
-			return rule(fl.Field(), fl.Param())
-		}); e != nil {
-			return e
+// typeRules 同一标签在各声明类型下的验证规则(标签只作用于声明它的类型)
+type typeRules map[reflect.Type]FieldValidRuleFn
+
+// lookup 查找字段所属结构体声明的规则，未声明时使用被验证对象(外层)声明的规则
+func (r typeRules) lookup(parent reflect.Value, rootTyp reflect.Type) (FieldValidRuleFn, bool) {
+	if parent.IsValid() {
+		if rule, ok := r[indirectType(parent.Type())]; ok {
+			return rule, true
 		}
 	}
-	return nil
+	rule, ok := r[rootTyp]
+	return rule, ok
 }
 
-// validExtra 注册额外验证规则
-func (v *Validator) validExtra(obj any, sl validator.StructLevel, scene Scene) {
-	// 处理嵌入字段的验证规则
-	_ = v.processEmbeddedValidations(obj, scene, 2, sl)
+// ownImpl 判断obj自身(非组合类型提升的方法)是否实现了接口，组合类型的规则由嵌入字段处理，避免重复执行
+func ownImpl[T any](obj any, method string) (T, bool) {
+	impl, ok := obj.(T)
+	if !ok || isPromoted(reflect.TypeOf(obj), method) {
+		var zero T
+		return zero, false
+	}
+	return impl, true
+}
+
+// promotedKey 提升方法缓存键
+type promotedKey struct {
+	typ    reflect.Type
+	method string
+}
+
+var (
+	promotedCache sync.Map // promotedKey -> bool
+	ownMethods    sync.Map // reflect.Type -> map[string]bool
+)
 
-	ev, ok := obj.(IExtraValidator)
+// ruleMethods 规则方法(类型覆盖嵌入类型的规则方法时需要声明)
+var ruleMethods = []string{
+	"ValidFieldRules", "ValidFieldDeps", "ValidRules", "ValidExtraRules", "ValidExtraKeys",
+	"ValidStructRules", "ValidContextRules", "ValidLocalizeRules", "LocalizeParamKeys", "ViewRules",
+}
+
+// RegisterOwnMethods 声明类型自身实现(覆盖嵌入类型)的规则方法，在 init 中调用
+// 类型覆盖嵌入类型的规则方法时必须声明(未声明时验证返回错误)，声明后未列出的方法视为提升
+func RegisterOwnMethods(obj any, methods ...string) {
+	typ := indirectType(reflect.TypeOf(obj))
+	own := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		own[method] = true
+	}
+	ownMethods.Store(typ, own)
+	promotedCache.Range(func(key, _ any) bool {
+		if key.(promotedKey).typ == typ {
+			promotedCache.Delete(key)
+		}
+		return true
+	})
+}
+
+// isPromoted 判断方法是否由组合类型提升而来
+// 嵌入类型都没有该方法时为自身实现，有同名方法时按 RegisterOwnMethods 的声明判断，未声明时看类型自身是否声明了该方法
+func isPromoted(typ reflect.Type, method string) bool {
+	typ = indirectType(typ)
+	key := promotedKey{typ: typ, method: method}
+	if promoted, ok := promotedCache.Load(key); ok {
+		return promoted.(bool)
+	}
+
+	promoted := false
+	if typ.Kind() == reflect.Struct && embeddedHas(typ, method) {
+		if own, ok := ownMethods.Load(typ); ok {
+			promoted = !own.(map[string]bool)[method]
+		} else {
+			promoted = !declares(typ, method)
+		}
+	}
+	promotedCache.Store(key, promoted)
+	return promoted
+}
+
+// declares 类型自身是否声明了方法(提升的方法由编译器生成包装函数，没有源码位置)
+func declares(typ reflect.Type, method string) bool {
+	m, ok := reflect.PointerTo(typ).MethodByName(method)
 	if !ok {
-		return
+		return false
 	}
+	pc := m.Func.Pointer()
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return true
+	}
+	file, _ := fn.FileLine(pc)
+	return file != "<autogenerated>"
+}
 
-	// 获取验证规则
-	extra, sceneRules := ev.ValidExtraRules()
-	if (extra == nil) || (sceneRules == nil) {
-		return
+// checkOwnMethods 类型(含组合类型)覆盖了嵌入类型的规则方法但未通过 RegisterOwnMethods 声明时返回错误
+func checkOwnMethods(typ reflect.Type, visited map[reflect.Type]bool) error {
+	typ = indirectType(typ)
+	if typ.Kind() != reflect.Struct || visited[typ] {
+		return nil
 	}
+	visited[typ] = true
 
-	// 筛选出当前场景的验证规则
-	scenes := make([]Scene, 0)
-	for key := range sceneRules {
-		if key == SceneAll {
-			scenes = append(scenes, SceneAll) // 添加全局场景
-		} else if (key & scene) == scene {
-			scenes = append(scenes, key) // 添加当前场景(必须是全部包含)
+	if _, ok := ownMethods.Load(typ); !ok {
+		for _, method := range ruleMethods {
+			if embeddedHas(typ, method) && declares(typ, method) {
+				return fmt.Errorf("valid: %s overrides %s of its embedded field, declare with valid.RegisterOwnMethods", typ, method)
+			}
 		}
 	}
-
-	// 遍历所有场景的验证规则
-	tagRules := make(map[Tag]ExtraValidRuleInfo)
-	for _, s := range scenes {
-		if tRules := sceneRules[s]; tRules != nil {
-			for tag, rule := range tRules {
-				tagRules[tag] = rule // 合并验证规则
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous {
+			if err := checkOwnMethods(sf.Type, visited); err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
 
-	// 注册验证规则
-	for tag, rule := range tagRules {
-		value, exists := extra[string(tag)]
-		if (tag == TagRequired) && !exists {
-			sl.ReportError(value, rule.Field, rule.Field, string(tag), rule.Param)
+// embeddedHas 嵌入字段(含指针)的方法集是否包含该方法
+func embeddedHas(typ reflect.Type, method string) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.Anonymous {
 			continue
 		}
-		if exists && !rule.ValidFn(value) {
-			sl.ReportError(value, rule.Field, rule.Field, string(tag), rule.Param)
+		ft := sf.Type
+		if ft.Kind() != reflect.Pointer && ft.Kind() != reflect.Interface {
+			ft = reflect.PointerTo(ft)
+		}
+		if _, ok := ft.MethodByName(method); ok {
+			return true
 		}
 	}
+	return false
 }
 
-// validStruct 注册结构体验证规则
-func (v *Validator) validStruct(obj any, sl validator.StructLevel, scene Scene) {
-	// 筛选出当前场景的验证规则
-	scenes := make([]Scene, 0)
-	scenes = append(scenes, SceneAll) // 添加全局场景
-	scenes = append(scenes, scene)    // 添加当前场景(实现类判断)
+// indirectType 指针类型取元素类型
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
 
-	// 处理嵌入字段的验证规则(全局+当前)
-	for _, s := range scenes {
-		_ = v.processEmbeddedValidations(obj, s, 3, sl)
+// sceneRules 当前场景(全局+祖先+当前)的声明式规则(有缓存)
+func (v *Validator) sceneRules(obj any, scene Scene) RuleValidRule {
+	key := planKey{typ: indirectType(reflect.TypeOf(obj)), scene: scene}
+	if rules, ok := v.regRules.Load(key); ok {
+		return rules.(RuleValidRule)
 	}
 
-	sv, ok := obj.(IStructValidator)
-	if !ok {
-		return
+	rules := RuleValidRule{}
+	if rv, ok := ownImpl[IRuleValidator](obj, "ValidRules"); ok {
+		sceneRules := rv.ValidRules()
+		for _, s := range matchScenes(sceneRules, scene) {
+			for name, rule := range sceneRules[s] {
+				rules[name] = rule // 场景规则覆盖全局/祖先场景规则
+			}
+		}
 	}
+	actual, _ := v.regRules.LoadOrStore(key, rules)
+	return actual.(RuleValidRule)
+}
 
-	// 获取验证规则(全局+当前)
-	for _, s := range scenes {
-		sv.ValidStructRules(s, func(field any, fieldName FieldName, tag Tag, param string) {
-			sl.ReportError(field, string(fieldName), string(fieldName), string(tag), param)
-		})
+// valid 执行额外验证规则
+func (info ExtraValidRuleInfo) valid(value any) bool {
+	if info.ValidFn != nil {
+		return info.ValidFn(value)
+	}
+	if info.Rule != nil {
+		return info.Rule.Valid(value)
 	}
+	return true
 }
 
-// processEmbeddedValidations 递归注册组合类型的验证规则
-// ttt: 处理类型 1=字段, 2=额外, 3=结构体
+// processEmbeddedValidations 递归收集组合类型的字段验证规则(结构体级规则见 compiledPlan)
 func (v *Validator) processEmbeddedValidations(
 	obj any, scene Scene,
-	ttt int, sl validator.StructLevel,
+	tagRules map[Tag]typeRules,
 ) error {
 	val := reflect.ValueOf(obj)
 	typ := reflect.TypeOf(obj)
@@ -360,28 +535,10 @@ func (v *Validator) processEmbeddedValidations(
 			continue
 		}
 
-		// 递归处理嵌入字段
-		if err := v.processEmbeddedValidations(embedObj, scene, ttt, sl); err != nil {
+		// 字段验证(内部会递归处理嵌入字段的嵌入字段)
+		if err := v.validFields(embedObj, scene, tagRules); err != nil {
 			return err
 		}
-
-		// 根据处理类型执行对应验证
-		switch ttt {
-		case 1: // 字段验证
-			if fv, okk := embedObj.(IFieldValidator); okk {
-				if err := v.validFields(fv, scene); err != nil {
-					return err
-				}
-			}
-		case 2: // 额外验证
-			if ev, okk := embedObj.(IExtraValidator); okk {
-				v.validExtra(ev, sl, scene)
-			}
-		case 3: // 结构体验证
-			if sv, okk := embedObj.(IStructValidator); okk {
-				v.validStruct(sv, sl, scene)
-			}
-		}
 	}
 	return nil
 }
@@ -393,17 +550,47 @@ func (v *Validator) handleValidationError(
 	var invalidErr *validator.InvalidValidationError
 	if errors.As(e, &invalidErr) {
 		// -- 验证失败 --
-		return []*MsgErr{{Err: e, Msg: "invalid_object_validation"}}
+		return []*MsgErr{{Err: e, Msg: MsgInvalidObject}}
 	}
 
 	var validateErrs validator.ValidationErrors
 	if errors.As(e, &validateErrs) {
-		// -- 本地化错误注册 --
-		if rl, ok := obj.(ILocalizeValidator); ok {
-			return v.validLocalize(scene, obj, rl, validateErrs, true)
+		// -- 本地化错误注册(按字段所在结构体，嵌套结构体使用自身的规则) --
+		rootTyp := indirectType(reflect.TypeOf(obj))
+		var msgErrs []*MsgErr
+		for _, group := range groupByOwner(rootTyp, validateErrs) {
+			owner := obj
+			if group.typ != rootTyp {
+				owner = reflect.New(group.typ).Interface()
+			}
+			if rl, ok := ownImpl[ILocalizeValidator](owner, "ValidLocalizeRules"); ok {
+				msgErrs = append(msgErrs, v.validLocalize(scene, owner, rl, group.errs)...)
+			} else {
+				msgErrs = append(msgErrs, v.processEmbeddedLocalizes(scene, owner, group.errs)...)
+			}
 		}
+		msgErrs = v.appendRuleErrs(obj, scene, msgErrs, validateErrs)
+		msgErrs = appendExtraKeyErrs(msgErrs, validateErrs)
+		msgErrs = appendFallbackErrs(msgErrs, validateErrs)
+
+		// -- 补充字段路径等信息 --
+		viewRules := map[reflect.Type]*ViewRule{}
+		for _, msgErr := range msgErrs {
+			ownerTyp := rootTyp
+			if msgErr.fe != nil {
+				ownerTyp = resolveNs(rootTyp, msgErr.fe.StructNamespace()).top
+			}
+			if viewRules[ownerTyp] == nil {
+				viewRules[ownerTyp] = sensitiveRules(reflect.New(ownerTyp).Interface())
+			}
+			if msgErr.Severity == "" && msgErr.fe != nil {
+				msgErr.Severity = v.extraSeverity(ownerTyp, scene, msgErr.fe) // 本地化规则未指定时使用额外规则声明的
+			}
+			msgErr.fill(obj, scene, viewRules[ownerTyp])
+		}
+		return msgErrs
 	}
-	return []*MsgErr{{Err: e, Msg: "unknown_validator_err"}}
+	return []*MsgErr{{Err: e, Msg: MsgUnknownValidator}}
 }
 
 // validLocalize 验证本地化错误
@@ -411,7 +598,6 @@ func (v *Validator) validLocalize(
 	scene Scene, obj any,
 	rl ILocalizeValidator,
 	validateErrs validator.ValidationErrors,
-	first bool,
 ) []*MsgErr {
 	var msgErrs []*MsgErr
 
@@ -421,31 +607,32 @@ func (v *Validator) validLocalize(
 	}
 
 	var localRule LocalizeValidRule
-	typ := reflect.TypeOf(obj)
-	cacheRules, ok := v.regLocs.Load(typ)
+	key := planKey{typ: reflect.TypeOf(obj), scene: scene}
+	cacheRules, ok := v.regLocs.Load(key)
 	if !ok {
 		// 没有就缓存，注册本地化规则
 		sceneRules := rl.ValidLocalizeRules()
 		if sceneRules == nil {
 			return msgErrs
 		}
-
-		// 筛选出当前场景的验证规则
-		scenes := make([]Scene, 0)
-		for key := range sceneRules {
-			if key == SceneAll {
-				scenes = append(scenes, SceneAll) // 添加全局场景
-			} else if (key & scene) == scene {
-				scenes = append(scenes, key) // 添加当前场景(必须是全部包含)
-			}
+		if e := CheckLocalizeRules(reflect.TypeOf(obj), sceneRules); e != nil {
+			return append(msgErrs, &MsgErr{Err: e, Msg: MsgUnknownValidator})
 		}
 
+		// 筛选出当前场景的验证规则(全局 -> 祖先场景 -> 当前场景)
+		scenes := matchScenes(sceneRules, scene)
+
 		// 遍历所有场景的验证规则1
 		tagFieldRules := make(map[Tag]map[FieldName]LocalizeValidRuleParam)
 		for _, s := range scenes {
 			if tRules := sceneRules[s]; tRules.Rule1 != nil {
 				for tag, rule := range tRules.Rule1 {
-					tagFieldRules[tag] = rule // 合并验证规则
+					if tagFieldRules[tag] == nil {
+						tagFieldRules[tag] = make(map[FieldName]LocalizeValidRuleParam)
+					}
+					for field, param := range rule {
+						tagFieldRules[tag][field] = param // 合并验证规则(按字段覆盖)
+					}
 				}
 			}
 		}
@@ -461,7 +648,7 @@ func (v *Validator) validLocalize(
 		}
 
 		localRule = LocalizeValidRule{Rule1: tagFieldRules, Rule2: tagRules}
-		v.regLocs.Store(typ, localRule)
+		v.regLocs.Store(key, localRule)
 	} else {
 		// 有就直接使用
 		localRule = cacheRules.(LocalizeValidRule)
@@ -473,15 +660,8 @@ func (v *Validator) validLocalize(
 		for tag, fieldRules := range localRule.Rule1 {
 			if ee.Tag() == string(tag) {
 				for field, rules := range fieldRules {
-					if ee.Field() == string(field) {
-						var params []any
-						if rules[2] != nil {
-							params = append(params, rules[2].([]any)...)
-						}
-						if rules[1].(bool) {
-							params = append(params, ee.Param())
-						}
-						msgErrs = append(msgErrs, &MsgErr{Msg: rules[0].(string), Params: params})
+					if ee.StructField() == string(field) {
+						msgErrs = append(msgErrs, rules.msgErr(ee))
 					}
 				}
 			}
@@ -489,36 +669,106 @@ func (v *Validator) validLocalize(
 		// -- 本地化错误注册(Tag) --
 		for tag, rules := range localRule.Rule2 {
 			if ee.Tag() == string(tag) {
-				var params []any
-				if rules[2] != nil {
-					params = append(params, rules[2].([]any)...)
-				}
-				if rules[1].(bool) {
-					params = append(params, ee.Param())
-				}
-				msgErrs = append(msgErrs, &MsgErr{Msg: rules[0].(string), Params: params})
+				msgErrs = append(msgErrs, rules.msgErr(ee))
 			}
 		}
 	}
+	return msgErrs
+}
 
-	// 找不到就返回默认
-	if (len(msgErrs) <= 0) && first {
-		// 提供更具体的错误信息，包括字段和规则
-		fieldErrors := make([]string, 0, len(validateErrs))
-		for _, err := range validateErrs {
-			fieldErrors = append(fieldErrors,
-				fmt.Sprintf("field:%s, tag:%s, param:%s",
-					err.Field(), err.Tag(), err.Param()))
+// appendRuleErrs 声明式规则的验证错误自动生成本地化信息 {label, 参数...}
+func (v *Validator) appendRuleErrs(
+	obj any, scene Scene,
+	msgErrs []*MsgErr, validateErrs validator.ValidationErrors,
+) []*MsgErr {
+	handled := make(map[validator.FieldError]bool, len(msgErrs))
+	for _, msgErr := range msgErrs {
+		handled[msgErr.fe] = true
+	}
+	rootTyp := indirectType(reflect.TypeOf(obj))
+	for _, ee := range validateErrs {
+		if handled[ee] {
+			continue
 		}
+		ref := resolveNs(rootTyp, ee.StructNamespace())
+		if ref.decl == nil {
+			continue
+		}
+		owner, name := ref.decl, ref.name
+		rule := v.sceneRules(reflect.New(owner).Interface(), scene)[name]
+		if rule == nil {
+			continue
+		}
+		c := rule.find(ee.Tag(), ee.Param())
+		if c == nil {
+			continue
+		}
+		var label any = i18n.Key(rule.label)
+		if rule.label == "" {
+			label = jsonFieldName(owner, name)
+		}
+		params := append([]any{label}, c.args...)
+		msgErrs = append(msgErrs, &MsgErr{Msg: rule.msgKey(c), Params: params, Severity: rule.severity, fe: ee})
+	}
+	return msgErrs
+}
 
+// appendFallbackErrs 没有本地化规则的验证错误返回默认错误
+func appendFallbackErrs(msgErrs []*MsgErr, validateErrs validator.ValidationErrors) []*MsgErr {
+	handled := make(map[validator.FieldError]bool, len(msgErrs))
+	for _, msgErr := range msgErrs {
+		handled[msgErr.fe] = true
+	}
+	for _, ee := range validateErrs {
+		if handled[ee] {
+			continue
+		}
+		// 提供更具体的错误信息，包括字段和规则
 		msgErrs = append(msgErrs, &MsgErr{
-			Msg:    "validation_failed",
-			Params: []any{strings.Join(fieldErrors, "; ")},
+			Msg: MsgValidationFailed,
+			Params: []any{fmt.Sprintf("field:%s, tag:%s, param:%s",
+				ee.Field(), ee.Tag(), ee.Param())},
+			fe: ee,
 		})
 	}
 	return msgErrs
 }
 
+// extraSeverity 额外验证错误的严重程度(外层规则优先，其次组合类型)
+func (v *Validator) extraSeverity(typ reflect.Type, scene Scene, ee validator.FieldError) Severity {
+	if typ == nil || typ.Kind() != reflect.Struct || !strings.HasPrefix(ee.StructField(), ExtraField+".") {
+		return ""
+	}
+	if ev, ok := ownImpl[IExtraValidator](reflect.New(typ).Interface(), "ValidExtraRules"); ok {
+		var severity Severity
+		_, sceneRules := ev.ValidExtraRules()
+		for _, s := range matchScenes(sceneRules, scene) {
+			if info, ok := sceneRules[s][Tag(ee.Tag())]; ok {
+				severity = info.severity() // 后者覆盖前者
+			}
+		}
+		if severity != "" {
+			return severity
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous {
+			if severity := v.extraSeverity(indirectType(sf.Type), scene, ee); severity != "" {
+				return severity
+			}
+		}
+	}
+	return ""
+}
+
+// severity 额外规则的严重程度
+func (info ExtraValidRuleInfo) severity() Severity {
+	if info.Severity == "" && info.Rule != nil {
+		return info.Rule.severity
+	}
+	return info.Severity
+}
+
 // processEmbeddedLocalizes 递归注册组合类型的本地化规则
 func (v *Validator) processEmbeddedLocalizes(
 	scene Scene, obj any,
@@ -569,22 +819,19 @@ func (v *Validator) processEmbeddedLocalizes(
 			continue
 		}
 
-		// 递归处理嵌入字段的本地化规则（无论是否实现接口）
-		if embedMsgErrs := v.processEmbeddedLocalizes(scene, embedObj, validateErrs); embedMsgErrs != nil {
-			allMsgErrs = append(allMsgErrs, embedMsgErrs...)
-		}
-
-		// 如果嵌入字段实现了ILocalizeValidator接口
-		if embedLocValidator, ok := embedObj.(ILocalizeValidator); ok {
+		// 如果嵌入字段实现了ILocalizeValidator接口(内部会递归处理嵌入字段的嵌入字段)
+		if embedLocValidator, ok := ownImpl[ILocalizeValidator](embedObj, "ValidLocalizeRules"); ok {
 			if msgErrs := v.validLocalize(
 				scene,
 				embedObj,
 				embedLocValidator,
 				validateErrs,
-				false,
 			); msgErrs != nil {
 				allMsgErrs = append(allMsgErrs, msgErrs...)
 			}
+		} else if embedMsgErrs := v.processEmbeddedLocalizes(scene, embedObj, validateErrs); embedMsgErrs != nil {
+			// 否则继续递归处理嵌入字段的本地化规则
+			allMsgErrs = append(allMsgErrs, embedMsgErrs...)
 		}
 	}
 
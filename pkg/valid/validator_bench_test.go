@@ -0,0 +1,150 @@
+package valid
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"katydid-mp-account/pkg/field"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// planCase 覆盖声明式规则/额外规则/结构体规则，quota 规则依赖对象当前值
+type planCase struct {
+	Name  string     `json:"name"`
+	Tags  []string   `json:"tags"`
+	Limit int        `json:"limit"`
+	Extra field.KMap `json:"extra"`
+}
+
+func (c *planCase) ValidRules() RuleValidRules {
+	return RuleValidRules{
+		SceneSave: RuleValidRule{
+			"Name": Runes(1, 20).Charset(Word),
+			"Tags": Slice(0, 3).Each(Runes(1, 5)),
+		},
+	}
+}
+
+func (c *planCase) ValidExtraRules() (field.KMap, ExtraValidRules) {
+	return c.Extra, ExtraValidRules{
+		SceneAll: ExtraValidRule{
+			"t-desc": {Field: "desc", Rule: Runes(0, 10)},
+		},
+		SceneAdd: ExtraValidRule{
+			"t-quota": {Field: "quota", ValidFn: func(value any) bool {
+				n, _ := value.(int)
+				return n <= c.Limit
+			}},
+		},
+	}
+}
+
+func (c *planCase) ValidStructRules(scene Scene, fn FuncReportError) {
+	if scene == SceneAdd && c.Limit < 0 {
+		fn(c.Limit, "Limit", "t-limit", "")
+	}
+}
+
+func newPlanCase() *planCase {
+	return &planCase{Name: "katydid", Tags: []string{"go"}, Limit: 10, Extra: field.KMap{"desc": "d", "quota": 5}}
+}
+
+func newPlanCaseInvalid() *planCase {
+	return &planCase{Name: "katydid!", Tags: []string{"", "toolong"}, Limit: -1, Extra: field.KMap{"desc": "description", "quota": 5}}
+}
+
+// uncompiledCheck 与 Check 相同，但每次验证重新生成计划(对照编译后的计划)
+func uncompiledCheck(tb testing.TB, obj any, scene Scene) func(obj any) (errs, warns []*MsgErr) {
+	tb.Helper()
+	v := Get()
+	validate, e := v.registerValidations(obj, scene)
+	if e != nil {
+		tb.Fatal(e)
+	}
+	nested := newNestedPlan(reflect.TypeOf(obj))
+	structTypes := []any{obj}
+	for _, typ := range nested.types {
+		structTypes = append(structTypes, reflect.New(typ).Interface())
+	}
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		v.compilePlan(sl.Current().Type(), scene).valid(sl)
+		nested.validDives(validate, sl)
+	}, structTypes...)
+	return func(obj any) (errs, warns []*MsgErr) {
+		if e := validate.Struct(obj); e != nil {
+			return splitSeverity(v.handleValidationError(obj, scene, e))
+		}
+		return nil, nil
+	}
+}
+
+// msgErrKeys MsgErr 可比较的部分(排序)
+func msgErrKeys(errs []*MsgErr) []string {
+	keys := make([]string, 0, len(errs))
+	for _, e := range errs {
+		keys = append(keys, fmt.Sprintf("%s|%s|%s|%v|%v|%s|%v", e.Field, e.Tag, e.Msg, e.Params, e.Value, e.Scene, e.Severity))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestCompiledPlanMatchesUncompiled(t *testing.T) {
+	objs := map[string]func() *planCase{"valid": newPlanCase, "invalid": newPlanCaseInvalid}
+	for name, newObj := range objs {
+		for _, scene := range []Scene{SceneBind, SceneAdd, SceneUpd, SceneGet} {
+			t.Run(name+"/"+scene.String(), func(t *testing.T) {
+				compiled, compiledWarns := Check(newObj(), scene)
+				uncompiled, uncompiledWarns := uncompiledCheck(t, newObj(), scene)(newObj())
+				if got, want := msgErrKeys(compiled), msgErrKeys(uncompiled); !slices.Equal(got, want) {
+					t.Fatalf("compiled = %v\nuncompiled = %v", got, want)
+				}
+				if got, want := msgErrKeys(compiledWarns), msgErrKeys(uncompiledWarns); !slices.Equal(got, want) {
+					t.Fatalf("compiled warns = %v\nuncompiled warns = %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestCompiledPlanLiveValue(t *testing.T) {
+	// 计划从零值生成(Limit=0)，quota 规则必须读取当前对象的 Limit
+	obj := newPlanCase()
+	if errs, _ := Check(obj, SceneAdd); len(errs) != 0 {
+		t.Fatalf("quota within limit: errs = %v", msgErrKeys(errs))
+	}
+	obj.Limit = 1
+	errs, _ := Check(obj, SceneAdd)
+	if keys := msgErrKeys(errs); len(keys) != 1 || errs[0].Tag != "t-quota" {
+		t.Fatalf("quota over limit: errs = %v", keys)
+	}
+}
+
+func BenchmarkCheck(b *testing.B) {
+	obj, invalid := newPlanCase(), newPlanCaseInvalid()
+	cases := []struct {
+		name  string
+		obj   *planCase
+		scene Scene
+	}{
+		{"bind", obj, SceneBind},
+		{"add", obj, SceneAdd},
+		{"add-invalid", invalid, SceneAdd},
+	}
+	for _, c := range cases {
+		b.Run("compiled/"+c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = Check(c.obj, c.scene)
+			}
+		})
+		b.Run("uncompiled/"+c.name, func(b *testing.B) {
+			check := uncompiledCheck(b, c.obj, c.scene)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = check(c.obj)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package valid
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type OwnBase struct{}
+
+func (b *OwnBase) ValidFieldRules() FieldValidRules { return nil }
+func (b *OwnBase) ViewRules() ViewRules             { return nil }
+
+// OwnPromoted 规则方法全部来自嵌入类型(无需声明)
+type OwnPromoted struct {
+	OwnBase
+}
+
+// OwnOverride 覆盖嵌入类型的 ViewRules，并有嵌入类型没有的 ValidStructRules
+type OwnOverride struct {
+	*OwnBase
+}
+
+func (o *OwnOverride) ViewRules() ViewRules                             { return nil }
+func (o *OwnOverride) ValidStructRules(scene Scene, fn FuncReportError) {}
+
+// OwnUndeclared 覆盖嵌入类型的方法但未声明
+type OwnUndeclared struct {
+	OwnBase
+}
+
+func (o *OwnUndeclared) ViewRules() ViewRules { return nil }
+
+func init() {
+	RegisterOwnMethods(&OwnOverride{}, "ViewRules")
+}
+
+func TestIsPromoted(t *testing.T) {
+	tests := []struct {
+		name   string
+		obj    any
+		method string
+		want   bool
+	}{
+		{"own without embedding", &OwnBase{}, "ViewRules", false},
+		{"promoted value embed", &OwnPromoted{}, "ValidFieldRules", true},
+		{"promoted pointer embed", &OwnOverride{}, "ValidFieldRules", true},
+		{"declared override", &OwnOverride{}, "ViewRules", false},
+		{"not in embedded", &OwnOverride{}, "ValidStructRules", false},
+		{"undeclared override", &OwnUndeclared{}, "ViewRules", false},
+		{"undeclared promoted", &OwnUndeclared{}, "ValidFieldRules", true},
+	}
+	for _, tt := range tests {
+		if got := isPromoted(reflect.TypeOf(tt.obj), tt.method); got != tt.want {
+			t.Errorf("%s: isPromoted(%T, %s) = %v, want %v", tt.name, tt.obj, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOwnMethods(t *testing.T) {
+	// 未覆盖嵌入类型的方法: 无需声明
+	if errs, _ := Check(&OwnPromoted{}, SceneAdd); len(errs) != 0 {
+		t.Fatalf("Check(OwnPromoted) = %v", errs)
+	}
+
+	// 覆盖但未声明: 返回错误(不 panic)
+	errs, _ := Check(&OwnUndeclared{}, SceneAdd)
+	if len(errs) != 1 || errs[0].Err == nil || !strings.Contains(errs[0].Err.Error(), "OwnUndeclared overrides ViewRules") {
+		t.Fatalf("Check(OwnUndeclared) = %v", errs)
+	}
+}
@@ -0,0 +1,203 @@
+package valid
+
+import (
+	"encoding/json"
+	"errors"
+	"katydid-mp-account/pkg/field"
+	"reflect"
+	"strings"
+)
+
+// Audience 响应受众，级别越高可见的字段越多
+type Audience uint8
+
+const (
+	AudiencePublic   Audience = 0 // 公开(任何人)
+	AudienceMember   Audience = 1 // 成员
+	AudienceAdmin    Audience = 2 // 管理员
+	AudienceInternal Audience = 3 // 内部(服务间调用，不对外)
+)
+
+// 响应视图(字段/Extra键可见性)
+type (
+	IViewer interface {
+		ViewRules() ViewRules
+	}
+
+	ViewRules = map[Scene]ViewRule
+	ViewRule  struct {
+		Fields       map[FieldName]Audience // 字段可见性，未声明的字段公开
+		Extra        map[string]Audience    // Extra键可见性，未声明的键使用 ExtraDefault
+		ExtraDefault *Audience              // 未声明的Extra键可见性(外层覆盖内层)，nil 时为 AudienceInternal(新增键需声明后才输出)
+	}
+)
+
+// extraAudience Extra键可见性
+func (r *ViewRule) extraAudience(key string) Audience {
+	if a, ok := r.Extra[key]; ok {
+		return a
+	}
+	if r.ExtraDefault != nil {
+		return *r.ExtraDefault
+	}
+	return AudienceInternal
+}
+
+// View 按受众裁剪响应(SceneRes)，返回可直接 json 序列化的 map
+func View(obj any, audience Audience) (map[string]any, error) {
+	return ViewScene(obj, SceneRes, audience)
+}
+
+// ViewJSON 按受众裁剪响应(SceneRes)并序列化
+func ViewJSON(obj any, audience Audience) ([]byte, error) {
+	data, err := View(obj, audience)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// ViewScene 按场景+受众裁剪响应，场景用于区分不同的响应形态(如列表/详情)
+func ViewScene(obj any, scene Scene, audience Audience) (map[string]any, error) {
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, errors.New("view object cannot be nil")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, errors.New("view object must be struct")
+	}
+
+	rule := ViewRule{Fields: map[FieldName]Audience{}, Extra: map[string]Audience{}}
+	collectViewRules(val, scene, &rule)
+
+	data := make(map[string]any, val.NumField())
+	viewStruct(val, audience, &rule, data)
+	return data, nil
+}
+
+// collectViewRules 递归合并组合类型的视图规则(外层覆盖内层)
+func collectViewRules(val reflect.Value, scene Scene, rule *ViewRule) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if !typ.Field(i).Anonymous {
+			continue
+		}
+		fieldVal := val.Field(i)
+		if fieldVal.Kind() == reflect.Ptr {
+			if fieldVal.IsNil() {
+				continue
+			}
+			fieldVal = fieldVal.Elem()
+		}
+		if fieldVal.Kind() == reflect.Struct {
+			collectViewRules(fieldVal, scene, rule)
+		}
+	}
+
+	var viewer IViewer
+	if val.CanAddr() {
+		viewer, _ = ownImpl[IViewer](val.Addr().Interface(), "ViewRules")
+	} else {
+		viewer, _ = ownImpl[IViewer](val.Interface(), "ViewRules")
+	}
+	if viewer == nil {
+		return
+	}
+	sceneRules := viewer.ViewRules()
+	for _, key := range matchScenes(sceneRules, scene) {
+		sRule := sceneRules[key]
+		for name, a := range sRule.Fields {
+			rule.Fields[name] = a
+		}
+		for k, a := range sRule.Extra {
+			rule.Extra[k] = a
+		}
+		if sRule.ExtraDefault != nil {
+			rule.ExtraDefault = sRule.ExtraDefault
+		}
+	}
+}
+
+// viewStruct 按json标签输出可见字段(组合类型平铺)
+func viewStruct(val reflect.Value, audience Audience, rule *ViewRule, data map[string]any) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		fieldVal := val.Field(i)
+
+		name, omitEmpty, skip := parseJSONTag(sf)
+		if skip {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			if fieldVal.Kind() == reflect.Ptr {
+				if fieldVal.IsNil() {
+					continue
+				}
+				fieldVal = fieldVal.Elem()
+			}
+			if fieldVal.Kind() == reflect.Struct {
+				viewStruct(fieldVal, audience, rule, data)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if a, ok := rule.Fields[FieldName(sf.Name)]; ok && a > audience {
+			continue
+		}
+		if omitEmpty && isEmptyValue(fieldVal) {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		if kMap, ok := fieldVal.Interface().(field.KMap); ok && kMap != nil {
+			data[name] = viewExtra(kMap, audience, rule)
+			continue
+		}
+		data[name] = fieldVal.Interface()
+	}
+}
+
+// viewExtra 输出可见的Extra键
+func viewExtra(extra field.KMap, audience Audience, rule *ViewRule) map[string]any {
+	data := make(map[string]any, len(extra))
+	for k, v := range extra.Redacted() {
+		if rule.extraAudience(k) > audience {
+			continue
+		}
+		data[k] = v
+	}
+	return data
+}
+
+// parseJSONTag 解析json标签 -> 名称,omitempty,是否忽略
+func parseJSONTag(sf reflect.StructField) (string, bool, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
+
+// isEmptyValue 同 encoding/json 的 omitempty 判断
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package valid
+
+import (
+	"katydid-mp-account/pkg/field"
+	"testing"
+)
+
+type ViewInner struct {
+	Extra field.KMap `json:"extra"`
+}
+
+func (v *ViewInner) ViewRules() ViewRules {
+	return ViewRules{
+		SceneAll: ViewRule{
+			Extra: map[string]Audience{"note": AudienceAdmin, "desc": AudiencePublic},
+		},
+	}
+}
+
+type ViewOpen struct {
+	ViewInner
+	Name string `json:"name"`
+}
+
+func init() {
+	RegisterOwnMethods(&ViewOpen{}, "ViewRules")
+}
+
+func (v *ViewOpen) ViewRules() ViewRules {
+	public := AudiencePublic
+	return ViewRules{
+		SceneAll: ViewRule{ExtraDefault: &public},
+	}
+}
+
+func TestViewExtraDefault(t *testing.T) {
+	extra := field.KMap{"desc": "d", "note": "n", "undeclared": "u"}
+	tests := []struct {
+		name     string
+		obj      any
+		audience Audience
+		want     []string
+	}{
+		{"closed public", &ViewInner{Extra: extra}, AudiencePublic, []string{"desc"}},
+		{"closed admin", &ViewInner{Extra: extra}, AudienceAdmin, []string{"desc", "note"}},
+		{"closed internal", &ViewInner{Extra: extra}, AudienceInternal, []string{"desc", "note", "undeclared"}},
+		{"open public", &ViewOpen{ViewInner: ViewInner{Extra: extra}}, AudiencePublic, []string{"desc", "undeclared"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := View(tt.obj, tt.audience)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := data["extra"].(map[string]any)
+			if len(got) != len(tt.want) {
+				t.Fatalf("extra = %v, want keys %v", got, tt.want)
+			}
+			for _, key := range tt.want {
+				if _, ok := got[key]; !ok {
+					t.Fatalf("extra = %v, missing %q", got, key)
+				}
+			}
+		})
+	}
+}